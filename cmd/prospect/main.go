@@ -5,6 +5,7 @@ import (
 	"errors"
 	"flag"
 	"github.com/djcrock/prospect/internal/web"
+	"github.com/djcrock/prospect/internal/web/room"
 	"log"
 	"net"
 	"net/http"
@@ -17,11 +18,22 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(simulate(os.Args[2:]))
+	}
+
 	var err error
 
 	bind := flag.String("bind", "", "interface to which the server will bind")
 	port := flag.Int("port", 8080, "port on which the server will listen")
 	isVersion := flag.Bool("version", false, "show build and version information")
+	stateDir := flag.String("state-dir", "", "directory in which games are saved across restarts (disabled if empty)")
+	lobbyTimeout := flag.Duration("lobby-timeout", time.Hour, "how long an unstarted game may sit idle before it is removed (0 to keep forever)")
+	activeTimeout := flag.Duration("active-timeout", 24*time.Hour, "how long a game in progress may sit idle before it is removed (0 to keep forever)")
+	finishedTimeout := flag.Duration("finished-timeout", time.Hour, "how long a finished game may sit idle before it is removed (0 to keep forever)")
+	maxSseClients := flag.Int("max-sse-clients", 10000, "maximum number of clients receiving live updates at once")
+	botDelay := flag.Duration("bot-delay", time.Second, "how long bots think before each move")
+	dbPath := flag.String("db", "", "SQLite database in which games and results are saved (overrides -state-dir)")
 
 	flag.Parse()
 
@@ -53,12 +65,43 @@ func main() {
 		logAddr = "localhost" + addr
 	}
 
-	app := web.NewApp(log.Default())
+	var store room.Store = room.NopStore{}
+	if *dbPath != "" {
+		sqlStore, err := room.NewSQLStore(*dbPath)
+		if err != nil {
+			log.Fatalf("failed to open database: %v", err)
+		}
+		defer sqlStore.Close()
+		store = sqlStore
+	} else if *stateDir != "" {
+		store, err = room.NewFileStore(*stateDir)
+		if err != nil {
+			log.Fatalf("failed to open state directory: %v", err)
+		}
+	}
+	rooms := room.NewCollection(store, *botDelay)
+	err = rooms.Load()
+	if err != nil {
+		log.Fatalf("failed to load saved games: %v", err)
+	}
+
+	janitorCtx, stopJanitor := context.WithCancel(context.Background())
+	defer stopJanitor()
+	go rooms.RunJanitor(janitorCtx, time.Minute, room.IdleTimeouts{
+		Lobby:    *lobbyTimeout,
+		Active:   *activeTimeout,
+		Finished: *finishedTimeout,
+	}, log.Default())
+
+	app := web.NewApp(log.Default(), rooms, *maxSseClients)
 
 	srv := &http.Server{
 		Addr:    addr,
 		Handler: app,
 	}
+	// Long-lived SSE streams never become idle on their own, so they are told
+	// to close once the server stops accepting new connections.
+	srv.RegisterOnShutdown(rooms.Shutdown)
 
 	allConnectionsClosed := make(chan struct{})
 
@@ -79,6 +122,13 @@ func main() {
 		}
 
 		log.Println("http server shutdown complete")
+
+		// Every change is saved as it is made, but take a final snapshot in
+		// case any of those saves failed.
+		err = rooms.SaveAll()
+		if err != nil {
+			log.Printf("error saving games: %v", err)
+		}
 		close(allConnectionsClosed)
 	}()
 
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/djcrock/prospect/internal/bot"
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// simulate runs the simulate subcommand, which plays games between bots
+// without the web server and reports how each strategy fared. It returns the
+// process's exit code.
+func simulate(args []string) int {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	games := flags.Int("games", 1000, "number of games to play at each player count")
+	players := flags.String("players", "2,3,4,5", "comma-separated player counts to play")
+	strategies := flags.String("bots", "easy,medium", "comma-separated bot strategies, seated in turn ("+strings.Join(bot.Strategies, ", ")+")")
+	seed := flags.Uint64("seed", 1, "seed from which every game is dealt, so that runs can be repeated")
+	parallel := flags.Int("parallel", runtime.GOMAXPROCS(0), "number of games to play at once")
+	flags.Parse(args)
+
+	counts, err := parsePlayerCounts(*players)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -players: %v\n", err)
+		return 2
+	}
+	seats := strings.Split(*strategies, ",")
+	for _, strategy := range seats {
+		if _, err := bot.New(strategy); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -bots: %q: %v\n", strategy, err)
+			return 2
+		}
+	}
+	if *games < 1 || *parallel < 1 {
+		fmt.Fprintln(os.Stderr, "-games and -parallel must be at least 1")
+		return 2
+	}
+
+	start := time.Now()
+	jobs := make(chan simulation)
+	results := make(chan simulationResult)
+	var wg sync.WaitGroup
+	for range *parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				results <- s.run()
+			}
+		}()
+	}
+	go func() {
+		for _, count := range counts {
+			for i := range *games {
+				jobs <- simulation{
+					seed:    *seed,
+					players: count,
+					index:   i,
+					seats:   seats,
+				}
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	stats := make(map[int]*simulationStats)
+	for _, count := range counts {
+		stats[count] = newSimulationStats(count)
+	}
+	for result := range results {
+		if result.err != nil && !errors.Is(result.err, bot.ErrStalled) {
+			fmt.Fprintf(os.Stderr, "game %d with %d players failed: %v\n", result.index, result.players, result.err)
+			return 1
+		}
+		stats[result.players].add(result)
+	}
+
+	for _, count := range counts {
+		stats[count].report(os.Stdout)
+	}
+	fmt.Printf("played %d games in %v\n", *games*len(counts), time.Since(start).Round(time.Millisecond))
+	return 0
+}
+
+func parsePlayerCounts(s string) ([]int, error) {
+	var counts []int
+	for _, field := range strings.Split(s, ",") {
+		count, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		if count < 2 || count > 5 {
+			return nil, fmt.Errorf("%d players: games have 2 to 5 players", count)
+		}
+		if !slices.Contains(counts, count) {
+			counts = append(counts, count)
+		}
+	}
+	return counts, nil
+}
+
+// simulation is a single game to be played between bots.
+type simulation struct {
+	seed    uint64
+	players int
+	// index numbers the game among those with the same number of players. It
+	// chooses the game's deal and who sits where.
+	index int
+	seats []string
+}
+
+type simulationResult struct {
+	players    int
+	index      int
+	strategies []string
+	points     []int
+	winners    []bool
+	rounds     []roundStats
+	err        error
+}
+
+type roundStats struct {
+	moves   int
+	wentOut bool
+}
+
+func (s simulation) run() simulationResult {
+	result := simulationResult{players: s.players, index: s.index}
+
+	// Every game has its own seed, so that its outcome does not depend on
+	// which worker played it, nor when.
+	gameSeed := [2]uint64{s.seed, uint64(s.players)<<32 | uint64(s.index)}
+	r := rand.New(rand.NewPCG(gameSeed[1], gameSeed[0]))
+
+	mode := game.ModeStandard
+	if s.players == 2 {
+		mode = game.ModeTwoPlayer
+	}
+	g := game.New(fmt.Sprintf("sim-%d-%d", s.players, s.index), mode, gameSeed)
+	bots := make([]bot.Bot, s.players)
+	for i := range bots {
+		// Rotate the seats so that every strategy gets to play from each seat
+		strategy := s.seats[(s.index+i)%len(s.seats)]
+		b, err := bot.NewWithRand(strategy, r)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		err = g.AddBot(strconv.Itoa(i), fmt.Sprintf("Bot %d", i+1), strategy)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		bots[i] = b
+		result.strategies = append(result.strategies, strategy)
+	}
+	if err := g.Start(); err != nil {
+		result.err = err
+		return result
+	}
+	result.err = bot.Play(g, bots)
+	if result.err != nil {
+		return result
+	}
+
+	best := g.Winners()[0].Points
+	for i := range g.Players {
+		result.points = append(result.points, g.Players[i].Points)
+		result.winners = append(result.winners, g.Players[i].Points == best)
+	}
+	moves := 0
+	for _, e := range g.Events {
+		switch e.Kind {
+		case game.EventPresent, game.EventProspect, game.EventPass:
+			moves++
+		case game.EventRoundEnd:
+			round := roundStats{moves: moves}
+			for i := range g.Players {
+				if g.Players[i].RoundResults[e.Round-1].WentOut {
+					round.wentOut = true
+				}
+			}
+			result.rounds = append(result.rounds, round)
+			moves = 0
+		}
+	}
+	return result
+}
+
+// simulationStats totals the results of the games with the same number of
+// players.
+type simulationStats struct {
+	players    int
+	games      int
+	stalled    int
+	rounds     int
+	moves      int
+	wentOut    int
+	strategies map[string]*strategyStats
+}
+
+type strategyStats struct {
+	seats  int
+	wins   int
+	points int
+}
+
+func newSimulationStats(players int) *simulationStats {
+	return &simulationStats{
+		players:    players,
+		strategies: make(map[string]*strategyStats),
+	}
+}
+
+func (s *simulationStats) add(result simulationResult) {
+	s.games++
+	if result.err != nil {
+		s.stalled++
+		return
+	}
+	for i, strategy := range result.strategies {
+		st, ok := s.strategies[strategy]
+		if !ok {
+			st = &strategyStats{}
+			s.strategies[strategy] = st
+		}
+		st.seats++
+		st.points += result.points[i]
+		if result.winners[i] {
+			st.wins++
+		}
+	}
+	for _, round := range result.rounds {
+		s.rounds++
+		s.moves += round.moves
+		if round.wentOut {
+			s.wentOut++
+		}
+	}
+}
+
+func (s *simulationStats) report(w io.Writer) {
+	fmt.Fprintf(w, "%d players: %d games", s.players, s.games)
+	if s.stalled > 0 {
+		fmt.Fprintf(w, " (%d stalled and were not counted)", s.stalled)
+	}
+	fmt.Fprintln(w)
+	if s.rounds == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+	fmt.Fprintf(w, "  rounds: %d, averaging %.1f moves\n", s.rounds, float64(s.moves)/float64(s.rounds))
+	fmt.Fprintf(w, "  ended by a player going out: %.1f%%\n", percent(s.wentOut, s.rounds))
+	fmt.Fprintf(w, "  ended by a presentation nobody beat: %.1f%%\n", percent(s.rounds-s.wentOut, s.rounds))
+
+	names := make([]string, 0, len(s.strategies))
+	for name := range s.strategies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "\tstrategy\tseats\twins\twin rate\tavg points\t")
+	for _, name := range names {
+		st := s.strategies[name]
+		fmt.Fprintf(tw, "\t%s\t%d\t%d\t%.1f%%\t%.2f\t\n",
+			name, st.seats, st.wins, percent(st.wins, st.seats), float64(st.points)/float64(st.seats))
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+}
+
+func percent(n, total int) float64 {
+	return 100 * float64(n) / float64(total)
+}
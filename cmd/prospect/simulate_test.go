@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParsePlayerCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{name: "single", input: "3", want: []int{3}},
+		{name: "several", input: "2,3,4,5", want: []int{2, 3, 4, 5}},
+		{name: "spaces", input: " 2, 5 ", want: []int{2, 5}},
+		{name: "duplicates", input: "4,2,4,2", want: []int{4, 2}},
+		{name: "too few players", input: "1,3", wantErr: true},
+		{name: "too many players", input: "3,6", wantErr: true},
+		{name: "not a number", input: "3,four", wantErr: true},
+		{name: "empty field", input: "3,,4", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePlayerCounts(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v; want error: %t", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulation_run(t *testing.T) {
+	// The hard bot is slow, so it only plays the smallest game
+	for _, s := range []simulation{
+		{seed: 7, players: 2, index: 1, seats: []string{"easy", "hard"}},
+		{seed: 7, players: 5, index: 1, seats: []string{"easy", "medium"}},
+	} {
+		first := s.run()
+		if first.err != nil {
+			t.Fatalf("unexpected error playing %d players: %v", s.players, first.err)
+		}
+		if len(first.points) != s.players || len(first.rounds) != s.players {
+			t.Errorf("got %d scores and %d rounds for %d players; want %d of each", len(first.points), len(first.rounds), s.players, s.players)
+		}
+
+		// The same seed plays out the same game
+		second := s.run()
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("got different results for %d players from the same seed:\n%+v\n%+v", s.players, first, second)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package bot provides computer players for Prospect.
+package bot
+
+import (
+	"errors"
+	"math/rand/v2"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// Bot chooses moves for a player. It sees only what the player can see.
+type Bot interface {
+	// Choose picks one of v.LegalActions, which must not be empty.
+	Choose(v *game.View) game.Action
+}
+
+// Strategies lists the strategies that may be passed to New, from easiest to
+// hardest.
+var Strategies = []string{"easy", "medium", "hard"}
+
+// DefaultStrategy is the strategy used when none is specified.
+const DefaultStrategy = "medium"
+
+var ErrUnknownStrategy = errors.New("unknown bot strategy")
+
+// New creates a Bot that plays using the named strategy.
+func New(strategy string) (Bot, error) {
+	return NewWithRand(strategy, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+}
+
+// NewWithRand creates a Bot that plays using the named strategy, taking any
+// random choices from r, so that its play can be reproduced.
+func NewWithRand(strategy string, r *rand.Rand) (Bot, error) {
+	switch strategy {
+	case "easy":
+		return &Random{Rand: r}, nil
+	// Bots were seated as "greedy" before there were difficulties
+	case "medium", "greedy":
+		return Greedy{}, nil
+	case "hard":
+		return &ISMCTS{Rand: r, Iterations: DefaultIterations}, nil
+	}
+	return nil, ErrUnknownStrategy
+}
+
+// Random is a Bot that picks uniformly from the legal actions.
+type Random struct {
+	Rand *rand.Rand
+}
+
+func (b *Random) Choose(v *game.View) game.Action {
+	return v.LegalActions[b.Rand.IntN(len(v.LegalActions))]
+}
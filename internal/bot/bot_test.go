@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// playGame plays a game between the given bots until it is over.
+func playGame(t testing.TB, seed uint64, bots ...Bot) *game.Game {
+	t.Helper()
+	mode := game.ModeStandard
+	if len(bots) == 2 {
+		mode = game.ModeTwoPlayer
+	}
+	g := game.New("game", mode, [2]uint64{seed, seed})
+	for i := range bots {
+		err := g.AddBot(fmt.Sprintf("%d", i), fmt.Sprintf("Bot %d", i), "test")
+		if err != nil {
+			t.Fatalf("unexpected error adding bot %d: %v", i, err)
+		}
+	}
+	err := g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+
+	err = Play(g, bots)
+	if err != nil {
+		t.Fatalf("unexpected error playing game: %v", err)
+	}
+	return g
+}
+
+func TestNew(t *testing.T) {
+	b, err := New(DefaultStrategy)
+	if err != nil || b == nil {
+		t.Fatalf("expected a bot for the default strategy, got %v, %v", b, err)
+	}
+	_, err = New("nonsense")
+	if err != ErrUnknownStrategy {
+		t.Fatalf("expected %v for an unknown strategy, got %v", ErrUnknownStrategy, err)
+	}
+}
+
+func TestGreedy(t *testing.T) {
+	for players := 2; players <= 5; players++ {
+		t.Run(fmt.Sprintf("%d players", players), func(t *testing.T) {
+			bots := make([]Bot, players)
+			for i := range bots {
+				bots[i] = Greedy{}
+			}
+			playGame(t, uint64(players), bots...)
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	playGame(t, 1, &Random{Rand: r}, &Random{Rand: r}, &Random{Rand: r})
+}
+
+func TestISMCTS(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	playGame(t, 1, &ISMCTS{Rand: r, Iterations: 20}, Greedy{}, &Random{Rand: r})
+}
+
+// benchmarkTournament plays whole games of the given bots against each other,
+// and reports each seat's mean points per game.
+func benchmarkTournament(b *testing.B, names []string, bots ...Bot) {
+	points := make([]float64, len(bots))
+	for i := range b.N {
+		// Rotate the seats so that no bot always goes first
+		seated := make([]Bot, len(bots))
+		for j := range bots {
+			seated[j] = bots[(i+j)%len(bots)]
+		}
+		g := playGame(b, uint64(i), seated...)
+		for j := range bots {
+			points[(i+j)%len(bots)] += float64(g.Players[j].Points)
+		}
+	}
+	for i, name := range names {
+		b.ReportMetric(points[i]/float64(b.N), name+"-points/game")
+	}
+}
+
+func BenchmarkTournament(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	b.Run("easy-medium-hard", func(b *testing.B) {
+		benchmarkTournament(b, []string{"easy", "medium", "hard"},
+			&Random{Rand: r}, Greedy{}, &ISMCTS{Rand: r, Iterations: DefaultIterations})
+	})
+	b.Run("easy-medium", func(b *testing.B) {
+		benchmarkTournament(b, []string{"easy", "medium"},
+			&Random{Rand: r}, Greedy{})
+	})
+	b.Run("medium-hard", func(b *testing.B) {
+		benchmarkTournament(b, []string{"medium", "hard"},
+			Greedy{}, &ISMCTS{Rand: r, Iterations: DefaultIterations})
+	})
+}
+
+func BenchmarkChoose(b *testing.B) {
+	g := game.New("game", game.ModeStandard, [2]uint64{1, 2})
+	for i := range 4 {
+		_ = g.AddBot(fmt.Sprintf("%d", i), "Bot", "test")
+	}
+	_ = g.Start()
+	for i := range g.Players {
+		_ = g.Apply(g.Players[i].Id, game.OrientAction{})
+	}
+	v := g.ViewFor(g.Players[g.CurrentPlayer].Id)
+
+	for _, strategy := range Strategies {
+		b.Run(strategy, func(b *testing.B) {
+			bot, err := New(strategy)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for range b.N {
+				bot.Choose(v)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// Greedy is a Bot that makes whichever move looks best right now, without
+// considering what other players might do. It presents the strongest
+// presentation it can, and otherwise keeps its hand as easy to present from as
+// possible.
+type Greedy struct{}
+
+func (Greedy) Choose(v *game.View) game.Action {
+	var best game.Action
+	bestScore := 0
+	var bestPresentation []game.Card
+	for _, action := range v.LegalActions {
+		if a, ok := action.(game.PresentAction); ok {
+			presentation := v.Hand[a.Start:a.End]
+			cmp := game.ComparePresentations(presentation, bestPresentation)
+			if bestPresentation != nil && cmp < 0 {
+				continue
+			}
+			score := scoreAction(v, action)
+			if bestPresentation == nil || cmp > 0 || score > bestScore {
+				best = action
+				bestScore = score
+				bestPresentation = presentation
+			}
+			continue
+		}
+		if bestPresentation != nil {
+			continue
+		}
+		score := scoreAction(v, action)
+		if best == nil || score > bestScore {
+			best = action
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// scoreAction estimates how good an action is for the viewer. Higher is
+// better.
+func scoreAction(v *game.View, action game.Action) int {
+	switch a := action.(type) {
+	case game.OrientAction:
+		hand := v.Hand
+		if a.Flip {
+			hand = flipHand(hand)
+		}
+		return handStrength(hand)
+	case game.PresentAction:
+		remaining := slices.Delete(slices.Clone(v.Hand), a.Start, a.End)
+		return handStrength(remaining)
+	case game.ProspectAction:
+		return handStrength(prospectedHand(v, a))
+	}
+	// Passing and confirming ready are only offered alongside moves that beat
+	// them, or alone
+	return 0
+}
+
+// handStrength estimates the value of a hand by how many cards could be
+// presented together.
+func handStrength(hand []game.Card) int {
+	strength := 0
+	for _, p := range game.GetValidPresentations(hand) {
+		strength += len(p) - 1
+	}
+	return strength
+}
+
+func flipHand(hand []game.Card) []game.Card {
+	flipped := make([]game.Card, len(hand))
+	for i := range hand {
+		flipped[i] = hand[i].Flip()
+	}
+	return flipped
+}
+
+// prospectedHand is the viewer's hand after making a prospect.
+func prospectedHand(v *game.View, a game.ProspectAction) []game.Card {
+	card := v.Presentation[len(v.Presentation)-1]
+	if a.Left {
+		card = v.Presentation[0]
+	}
+	if a.Flip {
+		card = card.Flip()
+	}
+	return slices.Insert(slices.Clone(v.Hand), a.Position, card)
+}
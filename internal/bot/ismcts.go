@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// DefaultIterations is the number of playouts ISMCTS makes per move by
+// default.
+const DefaultIterations = 1000
+
+// explorationWeight balances trying less explored moves against repeating
+// moves that have done well, in the UCB1 formula.
+const explorationWeight = 0.7
+
+// ISMCTS is a Bot that uses information set Monte Carlo tree search. The other
+// players' hands are hidden from it, so each playout starts from a different
+// guess at them, drawn from the cards it has not seen. Playouts are scored at
+// the end of the round.
+//
+// Choosing orientation and confirming ready are left to Greedy.
+type ISMCTS struct {
+	Rand       *rand.Rand
+	Iterations int
+}
+
+// node is a move in the search tree. Moves are only legal in some of the
+// guessed games, so each node counts how often it was available to be chosen
+// as well as how often it was.
+type node struct {
+	// player made the move leading to the node.
+	player       int
+	visits       int
+	reward       float64
+	availability int
+	children     map[game.Action]*node
+}
+
+func (b *ISMCTS) Choose(v *game.View) game.Action {
+	if v.Phase != game.PhasePlaying && v.Phase != game.PhaseAwaitingPresentOrPass {
+		return Greedy{}.Choose(v)
+	}
+	if len(v.LegalActions) == 1 {
+		return v.LegalActions[0]
+	}
+
+	root := &node{player: -1, children: make(map[game.Action]*node)}
+	for range b.Iterations {
+		b.iterate(root, game.Determinize(v, b.Rand))
+	}
+
+	// The most visited move is the most robust choice
+	var best game.Action
+	bestVisits := -1
+	for _, action := range v.LegalActions {
+		child, ok := root.children[action]
+		if ok && child.visits > bestVisits {
+			best = action
+			bestVisits = child.visits
+		}
+	}
+	if best == nil {
+		return Greedy{}.Choose(v)
+	}
+	return best
+}
+
+// iterate makes one playout in the guessed game g, and updates the tree with
+// its result.
+func (b *ISMCTS) iterate(root *node, g *game.Game) {
+	path := []*node{root}
+	n := root
+
+	// Select moves already in the tree
+	for g.IsPlaying() {
+		player := g.CurrentPlayer
+		actions := g.LegalActions(g.Players[player].Id)
+		var unexplored []game.Action
+		for _, action := range actions {
+			if _, ok := n.children[action]; !ok {
+				unexplored = append(unexplored, action)
+			}
+		}
+
+		if len(unexplored) > 0 {
+			// Expand the tree by one move
+			action := unexplored[b.Rand.IntN(len(unexplored))]
+			child := &node{player: player, children: make(map[game.Action]*node)}
+			n.children[action] = child
+			for _, a := range actions {
+				if c, ok := n.children[a]; ok {
+					c.availability++
+				}
+			}
+			mustApply(g, player, action)
+			path = append(path, child)
+			break
+		}
+
+		var best game.Action
+		bestScore := math.Inf(-1)
+		for _, action := range actions {
+			child := n.children[action]
+			child.availability++
+			score := child.reward/float64(child.visits) +
+				explorationWeight*math.Sqrt(math.Log(float64(child.availability))/float64(child.visits))
+			if score > bestScore {
+				best = action
+				bestScore = score
+			}
+		}
+		mustApply(g, player, best)
+		n = n.children[best]
+		path = append(path, n)
+	}
+
+	// Play out the rest of the round
+	rewards := playoutRewards(g.PlayOut(b.Rand))
+	for _, n := range path {
+		n.visits++
+		if n.player >= 0 {
+			n.reward += rewards[n.player]
+		}
+	}
+}
+
+func mustApply(g *game.Game, player int, action game.Action) {
+	err := g.Apply(g.Players[player].Id, action)
+	if err != nil {
+		panic("bot: legal action was rejected: " + err.Error())
+	}
+}
+
+// playoutRewards scales each player's score for a played out round from 0,
+// for the worst, to 1, for the best.
+func playoutRewards(scores []int) []float64 {
+	low, high := slices.Min(scores), slices.Max(scores)
+	rewards := make([]float64, len(scores))
+	for i, score := range scores {
+		if high == low {
+			rewards[i] = 0.5
+		} else {
+			rewards[i] = float64(score-low) / float64(high-low)
+		}
+	}
+	return rewards
+}
@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// maxMoves bounds the number of moves Play makes, since bots are not certain
+// to finish a round.
+const maxMoves = 10_000
+
+var ErrStalled = errors.New("game did not finish")
+
+// Play plays a started game to the end, with each player moved by the Bot in
+// the same seat of bots.
+func Play(g *game.Game, bots []Bot) error {
+	if len(bots) != len(g.Players) {
+		return fmt.Errorf("%d bots for %d players", len(bots), len(g.Players))
+	}
+	for moves := 0; !g.IsGameOver(); moves++ {
+		if moves >= maxMoves {
+			return ErrStalled
+		}
+		acted := false
+		for i := range g.Players {
+			playerId := g.Players[i].Id
+			v := g.ViewFor(playerId)
+			if len(v.LegalActions) == 0 {
+				continue
+			}
+			action := bots[i].Choose(v)
+			err := g.Apply(playerId, action)
+			if err != nil {
+				return fmt.Errorf("bot %d chose %#v: %w", i, action, err)
+			}
+			acted = true
+			break
+		}
+		if !acted {
+			return fmt.Errorf("no legal actions during phase %v", g.Phase)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package game
+
+// Action is a move made by a player. It is one of OrientAction, PresentAction,
+// ProspectAction, PassAction or ReadyAction.
+type Action interface {
+	apply(g *Game, playerId string) error
+}
+
+// OrientAction decides whether to flip the player's hand at the start of a
+// round.
+type OrientAction struct {
+	Flip bool
+}
+
+// PresentAction presents the cards in Hand[Start:End].
+type PresentAction struct {
+	Start int
+	End   int
+}
+
+// ProspectAction takes the card from the left or right end of the
+// presentation, optionally flips it, and inserts it into the player's hand at
+// Position.
+type ProspectAction struct {
+	Left     bool
+	Flip     bool
+	Position int
+}
+
+// PassAction declines to present after prospecting.
+type PassAction struct{}
+
+// ReadyAction confirms the player is ready for the next round.
+type ReadyAction struct{}
+
+func (a OrientAction) apply(g *Game, playerId string) error {
+	return g.DecideHandOrientation(playerId, a.Flip)
+}
+
+func (a PresentAction) apply(g *Game, playerId string) error {
+	return g.Present(playerId, a.Start, a.End)
+}
+
+func (a ProspectAction) apply(g *Game, playerId string) error {
+	return g.Prospect(playerId, a.Left, a.Flip, a.Position)
+}
+
+func (a PassAction) apply(g *Game, playerId string) error {
+	return g.Pass(playerId)
+}
+
+func (a ReadyAction) apply(g *Game, playerId string) error {
+	return g.ConfirmReady(playerId)
+}
+
+// Apply performs an Action on behalf of a player.
+func (g *Game) Apply(playerId string, action Action) error {
+	return action.apply(g, playerId)
+}
+
+// LegalActions lists every Action the given player may currently make.
+func (g *Game) LegalActions(playerId string) []Action {
+	player, err := g.GetPlayerIndex(playerId)
+	if err != nil {
+		return nil
+	}
+	p := &g.Players[player]
+
+	switch g.Phase {
+	case PhaseOrienting:
+		if p.HasDecidedHandOrientation {
+			return nil
+		}
+		return []Action{OrientAction{Flip: false}, OrientAction{Flip: true}}
+	case PhaseRoundOver:
+		if p.IsReady {
+			return nil
+		}
+		return []Action{ReadyAction{}}
+	case PhasePlaying, PhaseAwaitingPresentOrPass:
+		if player != g.CurrentPlayer {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	var actions []Action
+	for _, presentation := range getPlayablePresentations(p.Hand, g.Presentation) {
+		// Presentations are slices of the hand, so their capacity gives away
+		// where they start
+		start := cap(p.Hand) - cap(presentation)
+		actions = append(actions, PresentAction{Start: start, End: start + len(presentation)})
+	}
+
+	if g.Phase == PhaseAwaitingPresentOrPass {
+		return append(actions, PassAction{})
+	}
+
+	ends := []bool{true}
+	if len(g.Presentation) > 1 {
+		ends = append(ends, false)
+	}
+	if len(g.Presentation) > 0 {
+		for _, left := range ends {
+			for _, flip := range []bool{false, true} {
+				for position := range len(p.Hand) + 1 {
+					actions = append(actions, ProspectAction{Left: left, Flip: flip, Position: position})
+				}
+			}
+		}
+	}
+
+	return actions
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestGame_LegalActions(t *testing.T) {
+	t.Run("orienting", func(t *testing.T) {
+		g := &Game{Phase: PhaseOrienting, Players: []Player{{Id: "0"}, {Id: "1", HasDecidedHandOrientation: true}}}
+		want := []Action{OrientAction{Flip: false}, OrientAction{Flip: true}}
+		if got := g.LegalActions("0"); !reflect.DeepEqual(got, want) {
+			t.Errorf("LegalActions() = %v, want %v", got, want)
+		}
+		if got := g.LegalActions("1"); got != nil {
+			t.Errorf("LegalActions() = %v, want nil", got)
+		}
+	})
+
+	t.Run("playing", func(t *testing.T) {
+		g := &Game{
+			Phase:         PhasePlaying,
+			CurrentPlayer: 1,
+			Presentation:  []Card{{3, 1}, {4, 2}},
+			Players: []Player{
+				{Id: "0"},
+				{Id: "1", Hand: []Card{{5, 1}, {6, 2}, {2, 3}}},
+			},
+		}
+		want := []Action{
+			PresentAction{Start: 0, End: 2},
+			ProspectAction{Left: true, Flip: false, Position: 0},
+			ProspectAction{Left: true, Flip: false, Position: 1},
+			ProspectAction{Left: true, Flip: false, Position: 2},
+			ProspectAction{Left: true, Flip: false, Position: 3},
+			ProspectAction{Left: true, Flip: true, Position: 0},
+			ProspectAction{Left: true, Flip: true, Position: 1},
+			ProspectAction{Left: true, Flip: true, Position: 2},
+			ProspectAction{Left: true, Flip: true, Position: 3},
+			ProspectAction{Left: false, Flip: false, Position: 0},
+			ProspectAction{Left: false, Flip: false, Position: 1},
+			ProspectAction{Left: false, Flip: false, Position: 2},
+			ProspectAction{Left: false, Flip: false, Position: 3},
+			ProspectAction{Left: false, Flip: true, Position: 0},
+			ProspectAction{Left: false, Flip: true, Position: 1},
+			ProspectAction{Left: false, Flip: true, Position: 2},
+			ProspectAction{Left: false, Flip: true, Position: 3},
+		}
+		if got := g.LegalActions("1"); !reflect.DeepEqual(got, want) {
+			t.Errorf("LegalActions() = %v, want %v", got, want)
+		}
+		if got := g.LegalActions("0"); got != nil {
+			t.Errorf("LegalActions() = %v, want nil", got)
+		}
+	})
+
+	t.Run("awaiting present or pass", func(t *testing.T) {
+		g := &Game{
+			Phase:        PhaseAwaitingPresentOrPass,
+			Presentation: []Card{{3, 1}},
+			Players:      []Player{{Id: "0", Hand: []Card{{5, 1}}}},
+		}
+		want := []Action{PresentAction{Start: 0, End: 1}, PassAction{}}
+		if got := g.LegalActions("0"); !reflect.DeepEqual(got, want) {
+			t.Errorf("LegalActions() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestGame_Apply(t *testing.T) {
+	for players := 2; players <= 5; players++ {
+		t.Run(fmt.Sprintf("%d players", players), func(t *testing.T) {
+			g := &Game{Rand: rand.New(rand.NewPCG(1, 2))}
+			if players == 2 {
+				g.Mode = ModeTwoPlayer
+			}
+			for i := range players {
+				err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+				if err != nil {
+					t.Fatalf("unexpected error adding player %d: %v", i, err)
+				}
+			}
+			err := g.Start()
+			if err != nil {
+				t.Fatalf("unexpected error starting game: %v", err)
+			}
+
+			err = g.PlayRandomly(rand.New(rand.NewPCG(3, 4)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error playing random moves: %v", err)
+			}
+		})
+	}
+}
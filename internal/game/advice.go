@@ -0,0 +1,151 @@
+package game
+
+import (
+	"math/rand/v2"
+)
+
+// adviceSimulations is the number of rounds AdviseOrientation plays out for
+// each orientation of the hand.
+const adviceSimulations = 100
+
+// givenAdvice is the advice given to each player in a round. Working it out
+// takes too long to do each time the Game is viewed.
+type givenAdvice struct {
+	round  int
+	advice map[string]OrientationAdvice
+}
+
+// OrientationAdvice compares keeping a hand the way it was dealt with flipping
+// it.
+type OrientationAdvice struct {
+	Keep OrientationScore
+	Flip OrientationScore
+}
+
+// OrientationScore rates one orientation of a hand.
+type OrientationScore struct {
+	// Presentations counts the groups of more than one card in the hand that
+	// could be presented together.
+	Presentations int
+	// Expected is the player's mean lead over the simulated rounds: their
+	// score less the average of the other players' scores. Going out early
+	// scores little, but costs everybody else their hands.
+	Expected float64
+}
+
+// ShouldFlip reports whether the hand is expected to do better flipped. Hands
+// expected to do equally well either way are kept unless flipping them makes
+// more presentations.
+func (a OrientationAdvice) ShouldFlip() bool {
+	if a.Flip.Expected != a.Keep.Expected {
+		return a.Flip.Expected > a.Keep.Expected
+	}
+	return a.Flip.Presentations > a.Keep.Presentations
+}
+
+// OrientationAdvisor returns a function that scores both orientations of a
+// player's hand, by the presentations each makes and by playing out the round
+// from each. It only uses what the player can see: the other players are
+// dealt hands from the cards the player does not hold. The function works
+// from a copy of the Game, so it may be called without holding any lock on
+// the Game, and takes a while. It must only be called once.
+//
+// The same player is given the same advice for the whole of a round.
+func (g *Game) OrientationAdvisor(playerId string) (func() OrientationAdvice, error) {
+	player, err := g.GetPlayerIndex(playerId)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.requirePhase(PhaseOrienting); err != nil {
+		return nil, err
+	}
+
+	v := g.view(playerId)
+	// Seeded from the round, so that the advice does not depend on when it
+	// was worked out.
+	r := rand.New(rand.NewPCG(g.Seed[0]+uint64(g.Round), g.Seed[1]+uint64(player)))
+	return func() OrientationAdvice {
+		hand := v.Hand
+		flipped := make([]Card, len(hand))
+		for i, c := range hand {
+			flipped[i] = c.Flip()
+		}
+
+		var advice OrientationAdvice
+		for _, o := range []struct {
+			hand  []Card
+			score *OrientationScore
+		}{{hand, &advice.Keep}, {flipped, &advice.Flip}} {
+			for _, p := range GetValidPresentations(o.hand) {
+				if len(p) > 1 {
+					o.score.Presentations++
+				}
+			}
+			v.Hand = o.hand
+			total := 0.0
+			for range adviceSimulations {
+				total += simulateRound(v, r)
+			}
+			o.score.Expected = total / adviceSimulations
+		}
+		return advice
+	}, nil
+}
+
+// AdviseOrientation works out a player's advice straight away. See
+// OrientationAdvisor.
+func (g *Game) AdviseOrientation(playerId string) (OrientationAdvice, error) {
+	advise, err := g.OrientationAdvisor(playerId)
+	if err != nil {
+		return OrientationAdvice{}, err
+	}
+	return advise(), nil
+}
+
+// GiveOrientationAdvice records the advice for a player in the given round,
+// which Views show them while they decide how to orient their hand. Advice
+// for a round that is over is ignored.
+func (g *Game) GiveOrientationAdvice(round int, playerId string, advice OrientationAdvice) {
+	if round != g.Round || g.Phase != PhaseOrienting {
+		return
+	}
+	if g.advice.round != round {
+		g.advice = givenAdvice{round: round, advice: make(map[string]OrientationAdvice)}
+	}
+	g.advice.advice[playerId] = advice
+}
+
+// HasOrientationAdvice reports whether a player has been given advice this
+// round.
+func (g *Game) HasOrientationAdvice(playerId string) bool {
+	_, ok := g.givenAdvice(playerId)
+	return ok
+}
+
+func (g *Game) givenAdvice(playerId string) (OrientationAdvice, bool) {
+	if g.advice.round != g.Round {
+		return OrientationAdvice{}, false
+	}
+	advice, ok := g.advice.advice[playerId]
+	return advice, ok
+}
+
+// simulateRound plays out the round from a guess at the other players' hands
+// in v, and returns the viewer's lead at the end of it.
+func simulateRound(v *View, r *rand.Rand) float64 {
+	g := Determinize(v, r)
+	g.Phase = PhasePlaying
+	for i := range g.Players {
+		g.Players[i].HasDecidedHandOrientation = true
+	}
+
+	lead := 0.0
+	for i, score := range g.PlayOut(r) {
+		if i == v.Viewer {
+			lead += float64(score)
+		} else {
+			lead -= float64(score) / float64(len(g.Players)-1)
+		}
+	}
+	return lead
+}
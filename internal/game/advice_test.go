@@ -0,0 +1,106 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGame_AdviseOrientation(t *testing.T) {
+	g := New("game", ModeStandard, [2]uint64{1, 2})
+	for i := range 3 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	err := g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+
+	// Flipped, the hand is a run of seven
+	g.Players[0].Hand = []Card{{9, 1}, {7, 2}, {8, 3}, {6, 4}, {9, 5}, {8, 6}, {9, 7}}
+	advice, err := g.AdviseOrientation("0")
+	if err != nil {
+		t.Fatalf("unexpected error advising: %v", err)
+	}
+	if !advice.ShouldFlip() {
+		t.Errorf("got advice %+v; want flip", advice)
+	}
+	if advice.Flip.Presentations <= advice.Keep.Presentations {
+		t.Errorf("got %d presentations flipped, %d kept; want more flipped", advice.Flip.Presentations, advice.Keep.Presentations)
+	}
+	again, _ := g.AdviseOrientation("0")
+	if again != advice {
+		t.Errorf("got advice %+v, then %+v; want the same", advice, again)
+	}
+
+	_, err = g.AdviseOrientation("x")
+	if !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("got error %v for a spectator; want %v", err, ErrPlayerNotFound)
+	}
+	for i := range g.Players {
+		err = g.DecideHandOrientation(g.Players[i].Id, false)
+		if err != nil {
+			t.Fatalf("unexpected error deciding hand orientation: %v", err)
+		}
+	}
+	_, err = g.AdviseOrientation("0")
+	if err == nil {
+		t.Error("got advice after orienting; want an error")
+	}
+}
+
+func TestGame_SetTraining(t *testing.T) {
+	g := New("game", ModeStandard, [2]uint64{1, 2})
+	for i := range 3 {
+		_ = g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+	}
+	err := g.SetTraining("x", true)
+	if !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("got error %v from a spectator; want %v", err, ErrPlayerNotFound)
+	}
+	if err = g.SetTraining("0", true); err != nil {
+		t.Fatalf("unexpected error turning on training mode: %v", err)
+	}
+	version := g.Version()
+	_ = g.SetTraining("1", true)
+	if g.Version() != version {
+		t.Error("turning on training mode again changed the game")
+	}
+	_ = g.Start()
+
+	// Views only show advice once it has been given
+	if g.ViewFor("0").OrientationAdvice != nil {
+		t.Error("expected no advice before it was given")
+	}
+	for i := range g.Players {
+		advice, err := g.AdviseOrientation(g.Players[i].Id)
+		if err != nil {
+			t.Fatalf("unexpected error advising player %d: %v", i, err)
+		}
+		g.GiveOrientationAdvice(g.Round, g.Players[i].Id, advice)
+	}
+	g.GiveOrientationAdvice(g.Round-1, "2", OrientationAdvice{})
+	for i := range g.Players {
+		if !g.HasOrientationAdvice(g.Players[i].Id) || g.ViewFor(g.Players[i].Id).OrientationAdvice == nil {
+			t.Errorf("expected advice for player %d in training mode", i)
+		}
+	}
+	if *g.ViewFor("2").OrientationAdvice == (OrientationAdvice{}) {
+		t.Error("expected advice for an earlier round to be ignored")
+	}
+	if g.ViewFor("x").OrientationAdvice != nil {
+		t.Error("got advice for a spectator")
+	}
+	_ = g.DecideHandOrientation("0", false)
+	if g.ViewFor("0").OrientationAdvice != nil {
+		t.Error("got advice after orienting")
+	}
+
+	_ = g.SetTraining("0", false)
+	if g.ViewFor("1").OrientationAdvice != nil {
+		t.Error("got advice with training mode off")
+	}
+}
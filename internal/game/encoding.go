@@ -0,0 +1,50 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+)
+
+// gameFields has the fields of Game without its methods, so that it can be
+// encoded without recursing into Game.MarshalJSON.
+type gameFields Game
+
+// MarshalJSON encodes the Game along with the state of its random source, so
+// that a decoded Game continues exactly where this one left off.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	var randState []byte
+	if g.source != nil {
+		var err error
+		randState, err = g.source.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(struct {
+		*gameFields
+		RandState []byte `json:",omitempty"`
+	}{(*gameFields)(g), randState})
+}
+
+// UnmarshalJSON decodes a Game encoded by MarshalJSON. If no random state was
+// encoded, the random source is restarted from Seed.
+func (g *Game) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*gameFields
+		RandState []byte
+	}{gameFields: (*gameFields)(g)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	g.source = rand.NewPCG(g.Seed[0], g.Seed[1])
+	if aux.RandState != nil {
+		err = g.source.UnmarshalBinary(aux.RandState)
+		if err != nil {
+			return err
+		}
+	}
+	g.Rand = rand.New(g.source)
+	return nil
+}
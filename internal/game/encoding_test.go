@@ -0,0 +1,59 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestGame_MarshalJSON(t *testing.T) {
+	g := New("game", ModeStandard, [2]uint64{1, 2})
+	for i := range 3 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	err := g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+
+	// Play both games in lockstep, round tripping one of them through JSON
+	// after every move. Dealing later rounds relies on the random state being
+	// restored.
+	decoded := g
+	roundTrip := func() {
+		t.Helper()
+		data, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatalf("unexpected error marshalling game: %v", err)
+		}
+		decoded = &Game{}
+		err = json.Unmarshal(data, decoded)
+		if err != nil {
+			t.Fatalf("unexpected error unmarshalling game: %v", err)
+		}
+		if !reflect.DeepEqual(g, decoded) {
+			t.Fatalf("expected decoded game to match after %d events, got:\n%+v\n%+v", len(g.Events), g, decoded)
+		}
+	}
+	roundTrip()
+	err = g.PlayRandomly(rand.New(rand.NewPCG(3, 4)), func(playerId string, action Action) bool {
+		err := decoded.Apply(playerId, action)
+		if err != nil {
+			t.Fatalf("unexpected error applying legal action to decoded game: %v", err)
+		}
+		// The action was recorded at a slightly different time
+		for j := range decoded.Events {
+			decoded.Events[j].Time = g.Events[j].Time
+		}
+		roundTrip()
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error playing random moves: %v", err)
+	}
+}
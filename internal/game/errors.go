@@ -0,0 +1,22 @@
+package game
+
+import "errors"
+
+// Errors returned when a player attempts something the rules do not allow.
+// Their messages are suitable for showing to the player.
+var (
+	ErrGameFull              = errors.New("the game is full")
+	ErrPlayerExists          = errors.New("you have already joined this game")
+	ErrPlayerNotFound        = errors.New("you are not playing in this game")
+	ErrGameStarted           = errors.New("the game has already started")
+	ErrNotEnoughPlayers      = errors.New("not enough players have joined")
+	ErrWrongPhase            = errors.New("that is not allowed at this point in the game")
+	ErrAlreadyOriented       = errors.New("you have already chosen your hand orientation")
+	ErrNotYourTurn           = errors.New("it is not your turn")
+	ErrNothingToProspect     = errors.New("there is nothing to prospect")
+	ErrOutOfRange            = errors.New("those cards are not in your hand")
+	ErrInvalidPresentation   = errors.New("those cards do not form a valid presentation")
+	ErrPresentationTooWeak   = errors.New("your presentation does not beat the current presentation")
+	ErrMustProspectOrPresent = errors.New("you must prospect or present")
+	ErrStaleVersion          = errors.New("the game has moved on since you last saw it")
+)
@@ -0,0 +1,160 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// EventKind identifies the state change recorded by an Event.
+type EventKind string
+
+const (
+	EventJoin     EventKind = "join"
+	EventLeave    EventKind = "leave"
+	EventStart    EventKind = "start"
+	EventOrient   EventKind = "orient"
+	EventPresent  EventKind = "present"
+	EventProspect EventKind = "prospect"
+	EventPass     EventKind = "pass"
+	EventReady    EventKind = "ready"
+	EventTraining EventKind = "training"
+	// EventRoundEnd is recorded by the Game itself when a round finishes. It
+	// is not replayed, since replaying the event that caused it recreates it.
+	EventRoundEnd EventKind = "roundEnd"
+)
+
+// Event is a single change to the state of a Game. Only the fields relevant
+// to its Kind are set.
+type Event struct {
+	Seq      int
+	Time     time.Time
+	Kind     EventKind
+	PlayerId string `json:",omitempty"`
+	// Name is set for EventJoin, as is Bot if the player is a bot.
+	Name string `json:",omitempty"`
+	Bot  string `json:",omitempty"`
+	// Flip is set for EventOrient and EventProspect.
+	Flip bool `json:",omitempty"`
+	// Left and Position are set for EventProspect.
+	Left     bool `json:",omitempty"`
+	Position int  `json:",omitempty"`
+	// Start and End are set for EventPresent.
+	Start int `json:",omitempty"`
+	End   int `json:",omitempty"`
+	// Round is set for EventRoundEnd.
+	Round int `json:",omitempty"`
+	// Training is set for EventTraining if training mode was turned on.
+	Training bool `json:",omitempty"`
+}
+
+// Log is everything required to reconstruct a Game: the seed of its random
+// source, and every Event in the order it occurred.
+type Log struct {
+	Id     string
+	Mode   Mode
+	Seed   [2]uint64
+	Events []Event
+}
+
+// New creates a Game in the lobby, with a random source seeded from seed.
+func New(id string, mode Mode, seed [2]uint64) *Game {
+	source := rand.NewPCG(seed[0], seed[1])
+	return &Game{
+		Id:     id,
+		Mode:   mode,
+		Seed:   seed,
+		Rand:   rand.New(source),
+		source: source,
+	}
+}
+
+// Version identifies the state of the Game. It increases with every change,
+// including across Replay, so an action made from a View of an older Version
+// can be recognized.
+func (g *Game) Version() int {
+	return len(g.Events)
+}
+
+// Log returns a copy of the Game's Log.
+func (g *Game) Log() Log {
+	return Log{
+		Id:     g.Id,
+		Mode:   g.Mode,
+		Seed:   g.Seed,
+		Events: append([]Event(nil), g.Events...),
+	}
+}
+
+// Replay reconstructs a Game from its Log. Replaying a prefix of the Events
+// reconstructs the Game as it was at that point.
+func Replay(log Log) (*Game, error) {
+	g := New(log.Id, log.Mode, log.Seed)
+	defer func() {
+		g.now = nil
+	}()
+
+	for i, e := range log.Events {
+		if e.Seq != i+1 {
+			return nil, fmt.Errorf("event %d: out of sequence at position %d", e.Seq, i+1)
+		}
+		g.now = func() time.Time {
+			return e.Time
+		}
+		if e.Kind != EventRoundEnd {
+			err := g.replayEvent(e)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %w", e.Seq, err)
+			}
+		}
+		if len(g.Events) < e.Seq || g.Events[e.Seq-1].Kind != e.Kind {
+			return nil, fmt.Errorf("event %d: %s did not occur on replay", e.Seq, e.Kind)
+		}
+	}
+
+	return g, nil
+}
+
+func (g *Game) replayEvent(e Event) error {
+	switch e.Kind {
+	case EventJoin:
+		return g.addPlayer(e.PlayerId, e.Name, e.Bot)
+	case EventLeave:
+		if !g.RemovePlayer(e.PlayerId) {
+			return ErrPlayerNotFound
+		}
+		return nil
+	case EventStart:
+		return g.Start()
+	case EventOrient:
+		return g.DecideHandOrientation(e.PlayerId, e.Flip)
+	case EventPresent:
+		return g.Present(e.PlayerId, e.Start, e.End)
+	case EventProspect:
+		return g.Prospect(e.PlayerId, e.Left, e.Flip, e.Position)
+	case EventPass:
+		return g.Pass(e.PlayerId)
+	case EventReady:
+		return g.ConfirmReady(e.PlayerId)
+	case EventTraining:
+		return g.SetTraining(e.PlayerId, e.Training)
+	}
+	return errors.New("unknown event kind: " + string(e.Kind))
+}
+
+// record appends an Event to the Game's log.
+func (g *Game) record(e Event) {
+	e.Seq = len(g.Events) + 1
+	if e.Kind == EventRoundEnd && len(g.Events) > 0 {
+		// Share the timestamp of the event that ended the round
+		e.Time = g.Events[len(g.Events)-1].Time
+	} else if g.now != nil {
+		e.Time = g.now()
+	} else {
+		// Drop the monotonic clock reading so that Events compare equal after
+		// being serialized.
+		e.Time = time.Now().UTC()
+	}
+	g.Events = append(g.Events, e)
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	g := New("game", ModeStandard, [2]uint64{1, 2})
+	for i := range 4 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	g.RemovePlayer("3")
+	err := g.SetTraining("0", true)
+	if err != nil {
+		t.Fatalf("unexpected error turning on training mode: %v", err)
+	}
+	err = g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+
+	assertReplayEqual := func(t *testing.T, log Log) {
+		t.Helper()
+		replayed, err := Replay(log)
+		if err != nil {
+			t.Fatalf("unexpected error replaying game: %v", err)
+		}
+		if !reflect.DeepEqual(g, replayed) {
+			t.Fatalf("expected replayed game to match after %d events, got:\n%+v\n%+v", len(g.Events), g, replayed)
+		}
+	}
+
+	// Play random legal moves, checking that every intermediate state can be
+	// reconstructed from the log.
+	err = g.PlayRandomly(rand.New(rand.NewPCG(3, 4)), func(string, Action) bool {
+		assertReplayEqual(t, g.Log())
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error playing random moves: %v", err)
+	}
+
+	t.Run("serialized log", func(t *testing.T) {
+		data, err := json.Marshal(g.Log())
+		if err != nil {
+			t.Fatalf("unexpected error marshalling log: %v", err)
+		}
+		var log Log
+		err = json.Unmarshal(data, &log)
+		if err != nil {
+			t.Fatalf("unexpected error unmarshalling log: %v", err)
+		}
+		assertReplayEqual(t, log)
+	})
+
+	t.Run("tampered log", func(t *testing.T) {
+		log := g.Log()
+		for i := range log.Events {
+			if log.Events[i].Kind == EventPresent {
+				log.Events[i].End = log.Events[i].Start
+				break
+			}
+		}
+		_, err := Replay(log)
+		if err == nil {
+			t.Fatal("expected error replaying tampered log")
+		}
+	})
+
+	t.Run("out of sequence log", func(t *testing.T) {
+		for _, seq := range []int{0, -1, 3} {
+			log := g.Log()
+			log.Events[0].Seq = seq
+			_, err := Replay(log)
+			if err == nil {
+				t.Errorf("expected error replaying log with first event numbered %d", seq)
+			}
+		}
+	})
+}
@@ -1,55 +1,89 @@
 package game
 
 import (
-	"errors"
 	"slices"
 )
 
 func (g *Game) AddPlayer(id, name string) error {
+	return g.addPlayer(id, name, "")
+}
+
+// AddBot seats a bot, which plays using the named strategy.
+func (g *Game) AddBot(id, name, strategy string) error {
+	return g.addPlayer(id, name, strategy)
+}
+
+func (g *Game) addPlayer(id, name, bot string) error {
+	if err := g.requirePhase(PhaseLobby); err != nil {
+		return err
+	}
 	if g.IsFull() {
-		return errors.New("game is full")
+		return ErrGameFull
 	}
 	if p := g.GetPlayerById(id); p != nil {
-		return errors.New("player already exists")
+		return ErrPlayerExists
 	}
 
-	g.Players = append(g.Players, Player{Id: id, Name: name})
+	g.Players = append(g.Players, Player{Id: id, Name: name, Bot: bot})
+	g.record(Event{Kind: EventJoin, PlayerId: id, Name: name, Bot: bot})
 
 	return nil
 }
 
-func (g *Game) RemovePlayer(id string) {
-	if g.Round > 0 {
-		return
+// RemovePlayer removes a player from the lobby, and reports whether they were
+// removed.
+func (g *Game) RemovePlayer(id string) bool {
+	if g.Phase != PhaseLobby {
+		return false
 	}
 	i, err := g.GetPlayerIndex(id)
 	if err != nil {
-		return
+		return false
 	}
 	g.Players = slices.Delete(g.Players, i, i+1)
+	g.record(Event{Kind: EventLeave, PlayerId: id})
+	return true
+}
+
+// SetTraining turns training mode on or off on behalf of a seated player.
+// In training mode, Views advise players on how to orient their hands.
+func (g *Game) SetTraining(playerId string, enabled bool) error {
+	if g.GetPlayerById(playerId) == nil {
+		return ErrPlayerNotFound
+	}
+	if g.Training == enabled {
+		return nil
+	}
+	g.Training = enabled
+	g.record(Event{Kind: EventTraining, PlayerId: playerId, Training: enabled})
+	return nil
 }
 
 func (g *Game) Start() error {
 	if !g.IsLobby() {
-		return errors.New("game already started")
+		return ErrGameStarted
 	}
 	if !g.HasEnoughPlayers() {
-		return errors.New("not enough players")
+		return ErrNotEnoughPlayers
 	}
+	g.record(Event{Kind: EventStart})
 	g.startRound()
 	return nil
 }
 
 func (g *Game) startRound() {
 	deck := GetDeck(len(g.Players))
+	g.Phase = PhaseOrienting
 	g.CurrentPlayer = g.Round
 	g.Round++
-	cardsPerPlayer := len(deck) / len(g.Players)
+	cardsPerPlayer := g.handSize(deck)
 	for i := range g.Players {
 		p := &g.Players[i]
 		p.HasDecidedHandOrientation = false
+		p.ProspectAndPresentChips = g.prospectAndPresentChips()
 		p.CanProspectAndPresent = true
 		p.Hand = make([]Card, cardsPerPlayer)
+		p.Revealed = make([]bool, cardsPerPlayer)
 		for handIndex := range cardsPerPlayer {
 			drawIndex := g.Rand.IntN(len(deck))
 			drawnCard := deck[drawIndex]
@@ -72,12 +106,16 @@ func (g *Game) DecideHandOrientation(playerId string, flip bool) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requirePhase(PhaseOrienting); err != nil {
+		return err
+	}
 	p := &g.Players[player]
 	if p.HasDecidedHandOrientation {
-		return errors.New("player already has selected orientation")
+		return ErrAlreadyOriented
 	}
 
 	p.HasDecidedHandOrientation = true
+	g.record(Event{Kind: EventOrient, PlayerId: playerId, Flip: flip})
 
 	if flip {
 		for i := range p.Hand {
@@ -85,6 +123,10 @@ func (g *Game) DecideHandOrientation(playerId string, flip bool) error {
 		}
 	}
 
+	if g.HavePlayersDecidedHandOrientation() {
+		g.Phase = PhasePlaying
+	}
+
 	return nil
 }
 
@@ -93,15 +135,18 @@ func (g *Game) Prospect(playerId string, left, flip bool, position int) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requirePhase(PhasePlaying); err != nil {
+		return err
+	}
 	if player != g.CurrentPlayer {
-		return errors.New("not your turn")
+		return ErrNotYourTurn
 	}
 	if len(g.Presentation) == 0 {
-		return errors.New("nothing to prospect")
+		return ErrNothingToProspect
 	}
 	p := &g.Players[g.CurrentPlayer]
-	if position > len(p.Hand) {
-		return errors.New("position out of range")
+	if position < 0 || position > len(p.Hand) {
+		return ErrOutOfRange
 	}
 
 	var card Card
@@ -116,11 +161,14 @@ func (g *Game) Prospect(playerId string, left, flip bool, position int) error {
 		card = card.Flip()
 	}
 
+	p.syncRevealed()
 	p.Hand = slices.Insert(p.Hand, position, card)
+	p.Revealed = slices.Insert(p.Revealed, position, true)
 	g.Players[g.LastPlayerToPresent].ProspectTokens++
+	g.record(Event{Kind: EventProspect, PlayerId: playerId, Left: left, Flip: flip, Position: position})
 
 	if p.CanProspectAndPresent && g.CanPlayerPresent(playerId) {
-		p.IsDecidingPresent = true
+		g.Phase = PhaseAwaitingPresentOrPass
 		return nil
 	}
 
@@ -134,39 +182,46 @@ func (g *Game) Present(playerId string, start, end int) error {
 	if err != nil {
 		return err
 	}
-	if player != g.CurrentPlayer {
-		return errors.New("not your turn")
+	if err := g.requirePhase(PhasePlaying, PhaseAwaitingPresentOrPass); err != nil {
+		return err
 	}
-	if !g.HavePlayersDecidedHandOrientation() {
-		return errors.New("waiting for players to select hand orientation")
+	if player != g.CurrentPlayer {
+		return ErrNotYourTurn
 	}
 	p := &g.Players[g.CurrentPlayer]
 	if start < 0 || start >= len(p.Hand) {
-		return errors.New("start is out of range")
+		return ErrOutOfRange
 	}
 	if end < start || end > len(p.Hand) {
-		return errors.New("end is out of range")
+		return ErrOutOfRange
 	}
 
 	newPresentation := append([]Card(nil), p.Hand[start:end]...)
 	if !IsValidPresentation(newPresentation) {
-		return errors.New("invalid presentation")
+		return ErrInvalidPresentation
 	}
 	if ComparePresentations(newPresentation, g.Presentation) <= 0 {
-		return errors.New("new presentation does not beat existing presentation")
+		return ErrPresentationTooWeak
 	}
 
 	p.ScorePile += len(g.Presentation)
+	g.Discarded = append(g.Discarded, g.Presentation...)
 	g.LastPlayerToPresent = g.CurrentPlayer
 	g.Presentation = newPresentation
 
 	// Remove the presented cards from the Player's hand
+	p.syncRevealed()
 	p.Hand = slices.Delete(p.Hand, start, end)
+	p.Revealed = slices.Delete(p.Revealed, start, end)
+	g.record(Event{Kind: EventPresent, PlayerId: playerId, Start: start, End: end})
 
 	// If the Player did a ProspectAndPresent, consume that opportunity
-	if p.IsDecidingPresent {
-		p.CanProspectAndPresent = false
-		p.IsDecidingPresent = false
+	if g.Phase == PhaseAwaitingPresentOrPass {
+		if p.ProspectAndPresentChips > 0 {
+			p.ProspectAndPresentChips--
+		}
+		p.CanProspectAndPresent = p.ProspectAndPresentChips > 0
+		g.Phase = PhasePlaying
 	}
 
 	if len(p.Hand) == 0 {
@@ -185,20 +240,37 @@ func (g *Game) Pass(playerId string) error {
 		return err
 	}
 	if player != g.CurrentPlayer {
-		return errors.New("not your turn")
+		return ErrNotYourTurn
 	}
-	p := &g.Players[g.CurrentPlayer]
-	if !p.IsDecidingPresent {
-		return errors.New("must prospect or present")
+	if g.Phase != PhaseAwaitingPresentOrPass {
+		return ErrMustProspectOrPresent
 	}
-	p.IsDecidingPresent = false
+	g.record(Event{Kind: EventPass, PlayerId: playerId})
+	g.Phase = PhasePlaying
 	g.nextTurn()
 	return nil
 }
 
+// syncRevealed resets Revealed if it is out of step with Hand, as it is for
+// Players set up directly rather than dealt to by the Game, or saved before
+// Revealed was recorded.
+func (p *Player) syncRevealed() {
+	if len(p.Revealed) != len(p.Hand) {
+		p.Revealed = make([]bool, len(p.Hand))
+	}
+}
+
 func (g *Game) nextTurn() {
 	g.CurrentPlayer = (g.CurrentPlayer + 1) % len(g.Players)
 	if g.CurrentPlayer == g.LastPlayerToPresent {
+		if g.Mode == ModeTwoPlayer {
+			// Rather than ending the round, the last presenter collects their
+			// own presentation and must present again to an empty table.
+			g.Players[g.CurrentPlayer].ScorePile += len(g.Presentation)
+			g.Discarded = append(g.Discarded, g.Presentation...)
+			g.Presentation = nil
+			return
+		}
 		g.endRound()
 	}
 }
@@ -206,18 +278,65 @@ func (g *Game) nextTurn() {
 func (g *Game) endRound() {
 	for i := range g.Players {
 		p := &g.Players[i]
-		p.Points += p.ScorePile
+		result := RoundResult{
+			Round:          g.Round,
+			CardsCollected: p.ScorePile,
+			ProspectTokens: p.ProspectTokens,
+			WentOut:        len(p.Hand) == 0,
+			EndedRound:     i == g.LastPlayerToPresent,
+		}
+		if !result.EndedRound {
+			result.HandPenalty = len(p.Hand)
+		}
+		if len(p.Hand) > 0 {
+			result.LeftoverHand = p.Hand
+		}
+		p.RoundResults = append(p.RoundResults, result)
+		p.Points += result.Score()
 		p.ScorePile = 0
-		p.Points += p.ProspectTokens
 		p.ProspectTokens = 0
-		if i != g.LastPlayerToPresent {
-			p.Points -= len(p.Hand)
-		}
+		p.IsReady = false
 		p.Hand = nil
+		p.Revealed = nil
 	}
+	g.Presentation = nil
+	g.Discarded = nil
+	g.record(Event{Kind: EventRoundEnd, Round: g.Round})
 
-	if g.IsGameOver() {
+	// Each player deals once, so there are as many rounds as players.
+	if g.Round == len(g.Players) {
+		g.Phase = PhaseGameOver
 		return
 	}
+	g.Phase = PhaseRoundOver
+}
+
+// ConfirmReady records that a player has seen the results of the last round.
+// Once every player is ready, the next round is dealt.
+func (g *Game) ConfirmReady(playerId string) error {
+	player, err := g.GetPlayerIndex(playerId)
+	if err != nil {
+		return err
+	}
+	if err := g.requirePhase(PhaseRoundOver); err != nil {
+		return err
+	}
+	g.Players[player].IsReady = true
+	g.record(Event{Kind: EventReady, PlayerId: playerId})
+
+	for i := range g.Players {
+		if !g.Players[i].IsReady {
+			return nil
+		}
+	}
 	g.startRound()
+	return nil
+}
+
+// requirePhase returns an error unless the Game is in one of the given phases.
+func (g *Game) requirePhase(phases ...Phase) error {
+	if slices.Contains(phases, g.Phase) {
+		return nil
+	}
+	return ErrWrongPhase
 }
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"math/rand/v2"
 	"reflect"
@@ -25,6 +26,17 @@ func TestGetDeck(t *testing.T) {
 			}
 		}
 	})
+	t.Run("2 players - 44 card deck without 9/10", func(t *testing.T) {
+		deck := GetDeck(2)
+		if len(deck) != 44 {
+			t.Fatalf("expected 44 card deck, got %v", len(deck))
+		}
+		for _, card := range deck {
+			if card == (Card{9, 10}) || card == (Card{10, 9}) {
+				t.Fatalf("deck contains invalid card: %v", card)
+			}
+		}
+	})
 	t.Run("3 players - 36 card deck with no 10s", func(t *testing.T) {
 		deck := GetDeck(3)
 		if len(deck) != 36 {
@@ -119,9 +131,62 @@ func TestGame_Start(t *testing.T) {
 	}
 }
 
+func TestGame_Phase(t *testing.T) {
+	g := &Game{Rand: rand.New(rand.NewPCG(1, 2))}
+	for i := range 3 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	assertPhase(t, g, PhaseLobby)
+
+	err := g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+	assertPhase(t, g, PhaseOrienting)
+	err = g.AddPlayer("3", "Player 3")
+	if !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase adding player after the game started, got %v", err)
+	}
+	err = g.Present("0", 0, 1)
+	if !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase presenting before hand orientations are decided, got %v", err)
+	}
+	err = g.ConfirmReady("0")
+	if !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase confirming ready during a round, got %v", err)
+	}
+
+	for i := range g.Players {
+		err = g.DecideHandOrientation(g.Players[i].Id, false)
+		if err != nil {
+			t.Fatalf("unexpected error deciding hand orientation: %v", err)
+		}
+	}
+	assertPhase(t, g, PhasePlaying)
+	err = g.DecideHandOrientation("0", true)
+	if !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase deciding hand orientation during play, got %v", err)
+	}
+	err = g.Pass("0")
+	if !errors.Is(err, ErrMustProspectOrPresent) {
+		t.Fatalf("expected ErrMustProspectOrPresent passing without prospecting, got %v", err)
+	}
+	err = g.Pass("1")
+	if !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("expected ErrNotYourTurn passing out of turn, got %v", err)
+	}
+	err = g.Pass("excess")
+	if !errors.Is(err, ErrPlayerNotFound) {
+		t.Fatalf("expected ErrPlayerNotFound for unknown player, got %v", err)
+	}
+}
+
 func TestGame_DecideHandOrientation(t *testing.T) {
 	t.Run("no flip", func(t *testing.T) {
-		g := &Game{Round: 1, Players: []Player{
+		g := &Game{Phase: PhaseOrienting, Round: 1, Players: []Player{
 			{Hand: []Card{{1, 2}, {3, 4}}},
 		}}
 		err := g.DecideHandOrientation("", false)
@@ -135,7 +200,7 @@ func TestGame_DecideHandOrientation(t *testing.T) {
 	})
 
 	t.Run("flip", func(t *testing.T) {
-		g := &Game{Round: 1, Players: []Player{
+		g := &Game{Phase: PhaseOrienting, Round: 1, Players: []Player{
 			{Hand: []Card{{1, 2}, {3, 4}}},
 		}}
 		err := g.DecideHandOrientation("", true)
@@ -152,6 +217,7 @@ func TestGame_DecideHandOrientation(t *testing.T) {
 func TestGame_Present(t *testing.T) {
 	t.Run("round ending presentation", func(t *testing.T) {
 		g := &Game{
+			Phase: PhasePlaying,
 			Round: 1,
 			Players: []Player{
 				{Id: "0", Hand: []Card{{1, 2}, {2, 3}}, Points: 1, ScorePile: 2, ProspectTokens: 3, HasDecidedHandOrientation: true},
@@ -170,6 +236,33 @@ func TestGame_Present(t *testing.T) {
 		if g.Players[1].Points != -2 {
 			t.Fatalf("expected -2 points for player 1, got %v", g.Players[1].Points)
 		}
+		wantResults := []RoundResult{
+			{Round: 1, CardsCollected: 2, ProspectTokens: 3, WentOut: true, EndedRound: true},
+			{Round: 1, HandPenalty: 2, LeftoverHand: []Card{{5, 6}, {7, 8}}},
+		}
+		for i := range wantResults {
+			if !reflect.DeepEqual(g.Players[i].RoundResults, wantResults[i:i+1]) {
+				t.Fatalf("expected player %d round results %v, got %v", i, wantResults[i:i+1], g.Players[i].RoundResults)
+			}
+		}
+		if !g.IsRoundOver() {
+			t.Fatal("expected round to be over")
+		}
+
+		err = g.ConfirmReady("0")
+		if err != nil {
+			t.Fatalf("unexpected error confirming ready for player 0: %v", err)
+		}
+		if g.Round != 1 {
+			t.Fatalf("expected game to wait for every player to be ready, got round %v", g.Round)
+		}
+		err = g.ConfirmReady("1")
+		if err != nil {
+			t.Fatalf("unexpected error confirming ready for player 1: %v", err)
+		}
+		if g.IsRoundOver() {
+			t.Fatal("expected next round to have started")
+		}
 		if g.Round != 2 {
 			t.Fatalf("expected game to enter round 2, got %v", g.Round)
 		}
@@ -180,6 +273,148 @@ func TestGame_Present(t *testing.T) {
 	})
 }
 
+func TestGame_IsGameOver(t *testing.T) {
+	g := &Game{
+		Phase: PhasePlaying,
+		Round: 2,
+		Players: []Player{
+			{Id: "0", Hand: []Card{{1, 2}}, HasDecidedHandOrientation: true, RoundResults: []RoundResult{{Round: 1}}},
+			{Id: "1", Hand: []Card{{5, 6}, {7, 8}}, HasDecidedHandOrientation: true, RoundResults: []RoundResult{{Round: 1}}},
+		},
+	}
+	if g.IsGameOver() {
+		t.Fatal("expected final round to be in progress")
+	}
+
+	err := g.Present("0", 0, 1)
+	if err != nil {
+		t.Fatalf("unexpected error presenting for player 0: %v", err)
+	}
+	if !g.IsGameOver() {
+		t.Fatal("expected game to be over after the final round")
+	}
+	if g.Round != 2 {
+		t.Fatalf("expected no further rounds to be dealt, got round %d", g.Round)
+	}
+}
+
+func TestGame_Winners(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []int
+		want   []string
+	}{
+		{"single winner", []int{3, 7, -2}, []string{"1"}},
+		{"tied winners", []int{5, 1, 5}, []string{"0", "2"}},
+		{"everyone tied", []int{0, 0, 0}, []string{"0", "1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Game{}
+			for i, points := range tt.points {
+				g.Players = append(g.Players, Player{Id: fmt.Sprintf("%d", i), Points: points})
+			}
+			var got []string
+			for _, p := range g.Winners() {
+				got = append(got, p.Id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Winners() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGame_Prospect(t *testing.T) {
+	newGame := func() *Game {
+		return &Game{
+			Phase:               PhasePlaying,
+			Round:               1,
+			CurrentPlayer:       1,
+			LastPlayerToPresent: 0,
+			Presentation:        []Card{{1, 2}, {2, 3}, {3, 4}},
+			Players: []Player{
+				{Id: "0", Hand: []Card{{5, 6}}, HasDecidedHandOrientation: true},
+				{Id: "1", Hand: []Card{{7, 8}, {9, 10}}, HasDecidedHandOrientation: true},
+				{Id: "2", Hand: []Card{{6, 7}}, HasDecidedHandOrientation: true},
+			},
+		}
+	}
+
+	t.Run("right end flipped", func(t *testing.T) {
+		g := newGame()
+		err := g.Prospect("1", false, true, 1)
+		if err != nil {
+			t.Fatalf("unexpected error prospecting: %v", err)
+		}
+		assertCardSlicesEqual(t, []Card{{1, 2}, {2, 3}}, g.Presentation)
+		assertCardSlicesEqual(t, []Card{{7, 8}, {4, 3}, {9, 10}}, g.Players[1].Hand)
+		if g.Players[0].ProspectTokens != 1 {
+			t.Fatalf("expected player 0 ProspectTokens to be 1, got %d", g.Players[0].ProspectTokens)
+		}
+	})
+
+	t.Run("position out of range", func(t *testing.T) {
+		for _, position := range []int{-1, 3} {
+			g := newGame()
+			err := g.Prospect("1", true, false, position)
+			if err == nil {
+				t.Fatalf("expected error prospecting into position %d", position)
+			}
+			assertCardSlicesEqual(t, []Card{{1, 2}, {2, 3}, {3, 4}}, g.Presentation)
+		}
+	})
+
+	t.Run("not your turn", func(t *testing.T) {
+		g := newGame()
+		err := g.Prospect("0", true, false, 0)
+		if err == nil {
+			t.Fatal("expected error prospecting out of turn")
+		}
+	})
+}
+
+func TestGame_Pass(t *testing.T) {
+	g := &Game{
+		Phase:               PhasePlaying,
+		Round:               1,
+		CurrentPlayer:       1,
+		LastPlayerToPresent: 0,
+		Presentation:        []Card{{1, 2}},
+		Players: []Player{
+			{Id: "0", Hand: []Card{{5, 6}}, HasDecidedHandOrientation: true},
+			{Id: "1", Hand: []Card{{7, 8}}, CanProspectAndPresent: true, HasDecidedHandOrientation: true},
+			{Id: "2", Hand: []Card{{6, 7}}, HasDecidedHandOrientation: true},
+		},
+	}
+
+	err := g.Pass("1")
+	if err == nil {
+		t.Fatal("expected error passing before prospecting")
+	}
+	err = g.Prospect("1", true, false, 0)
+	if err != nil {
+		t.Fatalf("unexpected error prospecting: %v", err)
+	}
+	if !g.IsDecidingPresent("1") {
+		t.Fatal("expected player 1 to be deciding to present")
+	}
+	err = g.Prospect("1", true, false, 0)
+	if err == nil {
+		t.Fatal("expected error prospecting twice in one turn")
+	}
+	err = g.Pass("1")
+	if err != nil {
+		t.Fatalf("unexpected error passing: %v", err)
+	}
+	if g.CurrentPlayer != 2 {
+		t.Fatalf("expected current player to be 2, got %d", g.CurrentPlayer)
+	}
+	if !g.Players[1].CanProspectAndPresent {
+		t.Fatal("expected player 1 to keep their prospect-and-present chip")
+	}
+}
+
 func TestGetPlayablePresentations(t *testing.T) {
 	type args struct {
 		hand         []Card
@@ -346,14 +581,14 @@ func TestGameplay(t *testing.T) {
 	if g.Players[2].ProspectTokens != 1 {
 		t.Fatalf("expected player 2 ProspectTokens to be 1, got %d", g.Players[2].ProspectTokens)
 	}
-	if !g.Players[0].IsDecidingPresent {
+	if !g.IsDecidingPresent("0") {
 		t.Fatal("expected player 0 to be deciding to present")
 	}
 	err = g.Pass("0")
 	if err != nil {
 		t.Fatalf("unexpected error passing player 0: %v", err)
 	}
-	if g.Players[0].IsDecidingPresent {
+	if g.IsDecidingPresent("0") {
 		t.Fatal("expected player 1 to have made their decision")
 	}
 	if !g.Players[0].CanProspectAndPresent {
@@ -385,13 +620,110 @@ func TestGameplay(t *testing.T) {
 	if g.Players[2].CanProspectAndPresent {
 		t.Fatal("expected player 2 to have used up their ProspectAndPresent")
 	}
-	if g.Players[2].IsDecidingPresent {
+	if g.IsDecidingPresent("2") {
 		t.Fatal("expected player 2 to have made their decision")
 	}
 	assertCardSlicesEqual(t, []Card{{1, 3}, {1, 8}}, g.Presentation)
 	assertCardSlicesEqual(t, []Card{{6, 8}, {4, 6}, {6, 7}, {6, 2}, {3, 7}, {2, 3}, {9, 6}, {4, 9}, {8, 2}, {8, 9}}, g.Players[2].Hand)
 }
 
+func TestTwoPlayerGameplay(t *testing.T) {
+	g := &Game{Mode: ModeTwoPlayer, Rand: rand.New(rand.NewPCG(1, 2))}
+	for i := range 2 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	err := g.AddPlayer("2", "Player 2")
+	if err == nil {
+		t.Fatal("expected error adding a third player to a two-player game")
+	}
+	err = g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+	for i := range g.Players {
+		if len(g.Players[i].Hand) != 11 {
+			t.Fatalf("expected player %d to be dealt 11 cards, got %d", i, len(g.Players[i].Hand))
+		}
+		if g.Players[i].ProspectAndPresentChips != 3 {
+			t.Fatalf("expected player %d to have 3 chips, got %d", i, g.Players[i].ProspectAndPresentChips)
+		}
+		err = g.DecideHandOrientation(g.Players[i].Id, false)
+		if err != nil {
+			t.Fatalf("unexpected error deciding hand orientation: %v", err)
+		}
+	}
+
+	g.Players[0].Hand = []Card{{1, 2}, {5, 6}, {5, 7}}
+	g.Players[1].Hand = []Card{{4, 3}, {8, 9}, {2, 1}}
+
+	err = g.Present("0", 0, 1)
+	if err != nil {
+		t.Fatalf("unexpected error presenting for player 0: %v", err)
+	}
+	err = g.Prospect("1", true, false, 0)
+	if err != nil {
+		t.Fatalf("unexpected error prospecting for player 1: %v", err)
+	}
+	err = g.Present("1", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error presenting for player 1: %v", err)
+	}
+	if g.Players[1].ProspectAndPresentChips != 2 || !g.Players[1].CanProspectAndPresent {
+		t.Fatalf("expected player 1 to have 2 chips left, got %d", g.Players[1].ProspectAndPresentChips)
+	}
+	err = g.Prospect("0", true, false, 2)
+	if err != nil {
+		t.Fatalf("unexpected error prospecting for player 0: %v", err)
+	}
+	err = g.Pass("0")
+	if err != nil {
+		t.Fatalf("unexpected error passing for player 0: %v", err)
+	}
+
+	// Play returns to player 1, whose presentation was taken, so they collect
+	// nothing and must present to an empty table rather than ending the round.
+	if g.Round != 1 {
+		t.Fatalf("expected round 1 to continue, got round %d", g.Round)
+	}
+	if g.CurrentPlayer != 1 {
+		t.Fatalf("expected current player to be 1, got %d", g.CurrentPlayer)
+	}
+	assertCardSlicesEqual(t, []Card{}, g.Presentation)
+
+	err = g.Present("1", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error presenting for player 1: %v", err)
+	}
+	err = g.Present("0", 1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error presenting for player 0: %v", err)
+	}
+	err = g.Prospect("1", true, false, 0)
+	if err != nil {
+		t.Fatalf("unexpected error prospecting for player 1: %v", err)
+	}
+	err = g.Pass("1")
+	if err != nil {
+		t.Fatalf("unexpected error passing for player 1: %v", err)
+	}
+
+	// Play returns to player 0, who collects the remaining card of their own
+	// presentation.
+	if g.Players[0].ScorePile != 2 {
+		t.Fatalf("expected player 0 ScorePile to be 2, got %d", g.Players[0].ScorePile)
+	}
+	assertCardSlicesEqual(t, []Card{}, g.Presentation)
+}
+
+func assertPhase(t *testing.T, g *Game, want Phase) {
+	if g.Phase != want {
+		t.Fatalf("expected phase %v, got %v", want, g.Phase)
+	}
+}
+
 func assertCardSlicesEqual(t *testing.T, a, b []Card) {
 	if len(a) != len(b) {
 		t.Fatalf("expected %d cards, got %d: %v, %v", len(a), len(b), a, b)
@@ -2,17 +2,103 @@ package game
 
 import (
 	"math/rand/v2"
+	"time"
 )
 
+// Mode selects the rules used for a Game.
+type Mode int
+
+const (
+	// ModeStandard is the base game for 3 to 5 players.
+	ModeStandard Mode = iota
+	// ModeTwoPlayer is the official variant for exactly 2 players. Each player
+	// is dealt 11 cards from the 44 card deck, holds 3 prospect-and-present
+	// chips per round, and the round only ends when a player empties their
+	// hand: when play returns to the last presenter, they collect their own
+	// presentation into their score pile and must present again.
+	ModeTwoPlayer
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeStandard:
+		return "Standard"
+	case ModeTwoPlayer:
+		return "Two-player"
+	}
+	return "Unknown"
+}
+
+// Phase is the stage of play that a Game is in. Each Game method is only
+// permitted in certain phases, and is responsible for moving the Game on to
+// the next one.
+type Phase int
+
+const (
+	// PhaseLobby is the period before the game starts, while players join.
+	PhaseLobby Phase = iota
+	// PhaseOrienting is the start of each round, while players decide whether
+	// to flip their hands.
+	PhaseOrienting
+	// PhasePlaying is the main part of each round, where the current player
+	// must prospect or present.
+	PhasePlaying
+	// PhaseAwaitingPresentOrPass follows a prospect by a player still holding
+	// a prospect-and-present chip, who may now present or pass.
+	PhaseAwaitingPresentOrPass
+	// PhaseRoundOver is the pause between rounds, until every player is ready.
+	PhaseRoundOver
+	// PhaseGameOver follows the final round.
+	PhaseGameOver
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseLobby:
+		return "Lobby"
+	case PhaseOrienting:
+		return "Orienting"
+	case PhasePlaying:
+		return "Playing"
+	case PhaseAwaitingPresentOrPass:
+		return "AwaitingPresentOrPass"
+	case PhaseRoundOver:
+		return "RoundOver"
+	case PhaseGameOver:
+		return "GameOver"
+	}
+	return "Unknown"
+}
+
 type Game struct {
 	Id                  string
+	Mode                Mode
+	Phase               Phase
 	Round               int
 	CurrentPlayer       int
 	LastPlayerToPresent int
 	Presentation        []Card
 	Players             []Player
+	Events              []Event
+	// Training is set if players are advised on their choices.
+	Training bool `json:",omitempty"`
+	// Discarded holds the cards gone to score piles this round. Everybody saw
+	// them presented, so they are public.
+	Discarded []Card
+
+	// Seed is the seed of Rand, recorded so that the Game can be replayed.
+	Seed [2]uint64
+	Rand *rand.Rand `json:"-"`
+
+	// source is the PCG underlying Rand, if the Game was created with New.
+	// Its state is saved alongside the Game.
+	source *rand.PCG
+
+	// now overrides the time at which Events are recorded during replay.
+	now func() time.Time
 
-	Rand *rand.Rand
+	// advice holds the OrientationAdvice given this round.
+	advice givenAdvice
 }
 
 type Player struct {
@@ -22,9 +108,37 @@ type Player struct {
 	Points                    int
 	ProspectTokens            int
 	ScorePile                 int
+	ProspectAndPresentChips   int
 	CanProspectAndPresent     bool
 	HasDecidedHandOrientation bool
-	IsDecidingPresent         bool
+	IsReady                   bool
+	RoundResults              []RoundResult
+	// Bot names the strategy playing for the Player, if the Player is a bot.
+	Bot string `json:",omitempty"`
+	// Revealed marks the cards in Hand that everybody saw the Player take
+	// from the table.
+	Revealed []bool
+}
+
+// RoundResult records how a Player scored in a completed round.
+type RoundResult struct {
+	Round          int
+	CardsCollected int
+	ProspectTokens int
+	HandPenalty    int
+	// WentOut is set if the Player emptied their hand.
+	WentOut bool
+	// EndedRound is set if the Player made the presentation that ended the
+	// round, either by going out or by nobody beating it. They are exempt from
+	// the hand penalty.
+	EndedRound bool
+	// LeftoverHand holds the cards remaining in the Player's hand.
+	LeftoverHand []Card
+}
+
+// Score is the number of points gained (or lost) in the round.
+func (r RoundResult) Score() int {
+	return r.CardsCollected + r.ProspectTokens - r.HandPenalty
 }
 
 type Card [2]int
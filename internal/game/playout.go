@@ -0,0 +1,195 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+// maxPlayoutMoves bounds a playout, since quick moves can hand a single card
+// back and forth between presenting and prospecting without the round ever
+// ending. Playouts that go on this long are scored where they stand.
+const maxPlayoutMoves = 200
+
+// Determinize guesses a complete Game consistent with what the viewer of v
+// can see, for playing out the rest of the round. The other players keep the
+// cards they were seen to take, and the rest of their hands are dealt from
+// the cards the viewer has not seen, each either way up. Players are given
+// their index as their id.
+func Determinize(v *View, r *rand.Rand) *Game {
+	g := New(v.Id, v.Mode, [2]uint64{r.Uint64(), r.Uint64()})
+	g.Phase = v.Phase
+	g.Round = v.Round
+	g.CurrentPlayer = v.CurrentPlayer
+	g.LastPlayerToPresent = v.LastPlayerToPresent
+	g.Presentation = slices.Clone(v.Presentation)
+	g.Discarded = slices.Clone(v.Discarded)
+
+	unseen := unseenCards(v)
+	r.Shuffle(len(unseen), func(i, j int) {
+		unseen[i], unseen[j] = unseen[j], unseen[i]
+	})
+
+	g.Players = make([]Player, len(v.Players))
+	for i, p := range v.Players {
+		player := Player{
+			Id:                        strconv.Itoa(i),
+			Name:                      p.Name,
+			Points:                    p.Points,
+			ProspectTokens:            p.ProspectTokens,
+			ScorePile:                 p.ScorePile,
+			ProspectAndPresentChips:   p.ProspectAndPresentChips,
+			CanProspectAndPresent:     p.CanProspectAndPresent,
+			HasDecidedHandOrientation: p.HasDecidedHandOrientation,
+			IsReady:                   p.IsReady,
+		}
+		if i == v.Viewer {
+			player.Hand = slices.Clone(v.Hand)
+		} else {
+			// Cards the player was seen to take stay where they were put
+			revealed := make(map[int]Card)
+			for _, c := range p.Revealed {
+				revealed[c.Position] = c.Card
+			}
+			player.Hand = make([]Card, 0, p.HandSize)
+			for j := range p.HandSize {
+				if c, ok := revealed[j]; ok {
+					player.Hand = append(player.Hand, c)
+					player.Revealed = append(player.Revealed, true)
+					continue
+				}
+				if len(unseen) == 0 {
+					continue
+				}
+				c := unseen[0]
+				unseen = unseen[1:]
+				if r.IntN(2) == 0 {
+					c = c.Flip()
+				}
+				player.Hand = append(player.Hand, c)
+				player.Revealed = append(player.Revealed, false)
+			}
+		}
+		g.Players[i] = player
+	}
+	return g
+}
+
+// unseenCards lists the cards of the deck that the viewer has not seen: those
+// that are not in their hand, on the table, discarded, or known to be in
+// another player's hand.
+func unseenCards(v *View) []Card {
+	seen := make(map[Card]bool)
+	for _, cards := range [][]Card{v.Hand, v.Presentation, v.Discarded} {
+		for _, c := range cards {
+			seen[c] = true
+			seen[c.Flip()] = true
+		}
+	}
+	for _, p := range v.Players {
+		for _, c := range p.Revealed {
+			seen[c.Card] = true
+			seen[c.Card.Flip()] = true
+		}
+	}
+	var unseen []Card
+	for _, c := range GetDeck(len(v.Players)) {
+		if !seen[c] {
+			unseen = append(unseen, c)
+		}
+	}
+	return unseen
+}
+
+// IsPlaying reports whether the round is under way, with every hand
+// oriented.
+func (g *Game) IsPlaying() bool {
+	return g.Phase == PhasePlaying || g.Phase == PhaseAwaitingPresentOrPass
+}
+
+// PlayOut finishes the round with quick moves: each player makes the
+// strongest presentation they can, and otherwise a random legal move. It
+// returns each player's score for the round. Rounds cut short are scored as
+// they stand.
+func (g *Game) PlayOut(r *rand.Rand) []int {
+	for moves := 0; g.IsPlaying() && moves < maxPlayoutMoves; moves++ {
+		p := &g.Players[g.CurrentPlayer]
+		err := g.Apply(p.Id, playoutAction(g, p, r))
+		if err != nil {
+			panic("game: legal action was rejected: " + err.Error())
+		}
+	}
+
+	scores := make([]int, len(g.Players))
+	for i := range g.Players {
+		p := &g.Players[i]
+		if g.IsPlaying() || len(p.RoundResults) == 0 {
+			scores[i] = p.ScorePile + p.ProspectTokens - len(p.Hand)
+			continue
+		}
+		scores[i] = p.RoundResults[len(p.RoundResults)-1].Score()
+	}
+	return scores
+}
+
+// playoutAction picks a move for the current player p quickly: the strongest
+// presentation if there is one, and otherwise any legal move.
+func playoutAction(g *Game, p *Player, r *rand.Rand) Action {
+	actions := g.LegalActions(p.Id)
+	var best Action
+	var bestPresentation []Card
+	for _, action := range actions {
+		present, ok := action.(PresentAction)
+		if !ok {
+			continue
+		}
+		presentation := p.Hand[present.Start:present.End]
+		if ComparePresentations(presentation, bestPresentation) > 0 {
+			best = action
+			bestPresentation = presentation
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return actions[r.IntN(len(actions))]
+}
+
+// maxRandomMoves bounds PlayRandomly, well beyond the length of any real game.
+const maxRandomMoves = 100_000
+
+// PlayRandomly plays random legal moves until the game is over, or until
+// moved, which is called after each move, returns false. Playing whole games
+// of random moves exercises every rule, so it is useful in tests. It returns
+// an error if nobody can move, a legal move is rejected, or the game does not
+// finish.
+func (g *Game) PlayRandomly(r *rand.Rand, moved func(playerId string, action Action) bool) error {
+	for moves := 0; !g.IsGameOver(); moves++ {
+		if moves >= maxRandomMoves {
+			return errors.New("the game did not finish")
+		}
+		var playerId string
+		var actions []Action
+		for i := range g.Players {
+			playerId = g.Players[i].Id
+			actions = g.LegalActions(playerId)
+			if len(actions) > 0 {
+				break
+			}
+		}
+		if len(actions) == 0 {
+			return fmt.Errorf("no legal actions during phase %v", g.Phase)
+		}
+		action := actions[r.IntN(len(actions))]
+		err := g.Apply(playerId, action)
+		if err != nil {
+			return fmt.Errorf("legal action %#v was rejected: %w", action, err)
+		}
+		if moved != nil && !moved(playerId, action) {
+			return nil
+		}
+	}
+	return nil
+}
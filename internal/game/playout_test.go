@@ -0,0 +1,110 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestDeterminize(t *testing.T) {
+	g := New("game", ModeStandard, [2]uint64{3, 3})
+	for i := range 3 {
+		_ = g.AddBot(fmt.Sprintf("%d", i), "Bot", "test")
+	}
+	_ = g.Start()
+	v := g.ViewFor("1")
+
+	r := rand.New(rand.NewPCG(1, 2))
+	d := Determinize(v, r)
+	if !slices.Equal(d.Players[1].Hand, v.Hand) {
+		t.Errorf("expected the viewer to keep their hand %v, got %v", v.Hand, d.Players[1].Hand)
+	}
+	seen := make(map[Card]bool)
+	for i, p := range d.Players {
+		if len(p.Hand) != v.Players[i].HandSize {
+			t.Errorf("expected %d cards for player %d, got %v", v.Players[i].HandSize, i, len(p.Hand))
+		}
+		for _, c := range p.Hand {
+			if seen[c] || seen[c.Flip()] {
+				t.Errorf("card %v was dealt twice", c)
+			}
+			seen[c] = true
+		}
+	}
+
+	t.Run("cards seen on the table", func(t *testing.T) {
+		// Play until another player has taken a card from the table, and
+		// cards have gone to a score pile
+		r := rand.New(rand.NewPCG(3, 4))
+		seenEnough := func() bool {
+			v = g.ViewFor("1")
+			return v.Phase == PhasePlaying && len(v.Discarded) > 0 &&
+				(len(v.Players[0].Revealed) > 0 || len(v.Players[2].Revealed) > 0)
+		}
+		err := g.PlayRandomly(r, func(string, Action) bool { return !seenEnough() })
+		if err != nil {
+			t.Fatalf("unexpected error playing random moves: %v", err)
+		}
+		if !seenEnough() {
+			t.Fatal("expected a card to be taken and discarded")
+		}
+
+		for range 20 {
+			d := Determinize(v, r)
+			seen := make(map[Card]bool)
+			for _, cards := range [][]Card{v.Presentation, v.Discarded} {
+				for _, c := range cards {
+					seen[c] = true
+					seen[c.Flip()] = true
+				}
+			}
+			for i, p := range d.Players {
+				for _, c := range v.Players[i].Revealed {
+					if p.Hand[c.Position] != c.Card {
+						t.Errorf("expected the %v player %d took at position %d of their hand, got %v", c.Card, i, c.Position, p.Hand[c.Position])
+					}
+				}
+				for _, c := range p.Hand {
+					if seen[c] {
+						t.Errorf("card %v was dealt, but has been seen elsewhere", c)
+					}
+					seen[c] = true
+					seen[c.Flip()] = true
+				}
+			}
+		}
+	})
+}
+
+func TestGame_PlayOut(t *testing.T) {
+	g := New("game", ModeStandard, [2]uint64{1, 2})
+	for i := range 4 {
+		_ = g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+	}
+	_ = g.Start()
+	for i := range g.Players {
+		_ = g.DecideHandOrientation(g.Players[i].Id, false)
+	}
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 20 {
+		d := Determinize(g.ViewFor("0"), r)
+		scores := d.PlayOut(r)
+		if len(scores) != len(g.Players) {
+			t.Fatalf("expected a score for each of %d players, got %v", len(g.Players), len(scores))
+		}
+		if d.IsPlaying() {
+			// Cut short, so scored as it stands
+			continue
+		}
+		for i, p := range d.Players {
+			if scores[i] != p.RoundResults[0].Score() {
+				t.Errorf("expected round score %d for player %d, got %v", p.RoundResults[0].Score(), i, scores[i])
+			}
+		}
+	}
+	if g.Phase != PhasePlaying || len(g.Events) != 5+len(g.Players) {
+		t.Error("playing out a guessed game changed the real one")
+	}
+}
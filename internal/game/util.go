@@ -2,13 +2,15 @@ package game
 
 import (
 	"cmp"
-	"errors"
 	"slices"
 )
 
 const minPlayers = 3
 const maxPlayers = 5
 
+const twoPlayerHandSize = 11
+const twoPlayerProspectAndPresentChips = 3
+
 var baseDeck = makeBaseDeck()
 
 func makeBaseDeck() []Card {
@@ -27,7 +29,7 @@ func GetDeck(players int) []Card {
 		// Omit all cards containing 10 (the first 9 cards)
 		cardsToRemove = 9
 	}
-	if players == 4 {
+	if players == 2 || players == 4 {
 		// Remove the 10/9 card (the first card)
 		cardsToRemove = 1
 	}
@@ -51,31 +53,113 @@ func (g *Game) GetPlayerIndex(id string) (int, error) {
 		}
 	}
 
-	return -1, errors.New("player not found")
+	return -1, ErrPlayerNotFound
 }
 
 func (g *Game) GetCurrentPlayer() *Player {
 	return &g.Players[g.CurrentPlayer]
 }
 
+// HasHumans reports whether any of the players is not a bot.
+func (g *Game) HasHumans() bool {
+	for i := range g.Players {
+		if g.Players[i].Bot == "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *Game) IsEmpty() bool {
 	return len(g.Players) == 0
 }
 
 func (g *Game) HasEnoughPlayers() bool {
+	if g.Mode == ModeTwoPlayer {
+		return len(g.Players) == 2
+	}
 	return len(g.Players) >= minPlayers
 }
 
 func (g *Game) IsFull() bool {
+	if g.Mode == ModeTwoPlayer {
+		return len(g.Players) >= 2
+	}
 	return len(g.Players) >= maxPlayers
 }
 
+// handSize is the number of cards dealt to each player from the given deck.
+func (g *Game) handSize(deck []Card) int {
+	if g.Mode == ModeTwoPlayer {
+		return twoPlayerHandSize
+	}
+	return len(deck) / len(g.Players)
+}
+
+// prospectAndPresentChips is the number of times each player may present
+// immediately after prospecting in a single round.
+func (g *Game) prospectAndPresentChips() int {
+	if g.Mode == ModeTwoPlayer {
+		return twoPlayerProspectAndPresentChips
+	}
+	return 1
+}
+
 func (g *Game) IsLobby() bool {
-	return g.Round == 0
+	return g.Phase == PhaseLobby
+}
+
+func (g *Game) IsRoundOver() bool {
+	return g.Phase == PhaseRoundOver
 }
 
 func (g *Game) IsGameOver() bool {
-	return g.Round == len(g.Players)
+	return g.Phase == PhaseGameOver
+}
+
+// IsDecidingPresent reports whether the given player has just prospected and
+// must now decide whether to present or pass.
+func (g *Game) IsDecidingPresent(playerId string) bool {
+	player, err := g.GetPlayerIndex(playerId)
+	if err != nil {
+		return false
+	}
+	return g.Phase == PhaseAwaitingPresentOrPass && player == g.CurrentPlayer
+}
+
+// Standings lists the players from most to fewest points. Players with equal
+// points retain their seating order.
+func (g *Game) Standings() []*Player {
+	return standings(g.Players, func(p *Player) int { return p.Points })
+}
+
+// Winners lists the players with the most points. More than one player is
+// returned in the event of a tie.
+func (g *Game) Winners() []*Player {
+	return winners(g.Players, func(p *Player) int { return p.Points })
+}
+
+// standings implements Standings for both Players and PlayerViews.
+func standings[T any](players []T, points func(*T) int) []*T {
+	standings := make([]*T, len(players))
+	for i := range players {
+		standings[i] = &players[i]
+	}
+	slices.SortStableFunc(standings, func(a, b *T) int {
+		return cmp.Compare(points(b), points(a))
+	})
+	return standings
+}
+
+// winners implements Winners for both Players and PlayerViews.
+func winners[T any](players []T, points func(*T) int) []*T {
+	standings := standings(players, points)
+	for i := range standings {
+		if points(standings[i]) != points(standings[0]) {
+			return standings[:i]
+		}
+	}
+	return standings
 }
 
 func (g *Game) HavePlayersDecidedHandOrientation() bool {
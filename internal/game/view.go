@@ -0,0 +1,180 @@
+package game
+
+import (
+	"slices"
+)
+
+// View is the part of a Game that one viewer is allowed to see: everything
+// public, plus the viewer's own hand. Player ids are omitted, since they are
+// used to authenticate players. Views share no memory with the Game, so they
+// may be used after the Game has moved on.
+type View struct {
+	Id                  string
+	Version             int // the Game's Version when the View was taken
+	Mode                Mode
+	Phase               Phase
+	Round               int
+	CurrentPlayer       int
+	LastPlayerToPresent int
+	Presentation        []Card
+	Players             []PlayerView
+	// Viewer is the index of the viewer in Players, or -1 if the viewer is
+	// not seated in the Game.
+	Viewer int
+	// Hand is the viewer's own hand.
+	Hand []Card
+	// LegalActions lists the actions currently available to the viewer.
+	LegalActions []Action
+	// Training is set if the Game is in training mode.
+	Training bool
+	// OrientationAdvice is set in training mode while the viewer is deciding
+	// how to orient their hand, once they have been given advice.
+	OrientationAdvice *OrientationAdvice
+	// Discarded lists the cards gone to score piles this round.
+	Discarded []Card
+
+	isFull           bool
+	hasEnoughPlayers bool
+}
+
+// PlayerView is the publicly visible state of a Player.
+type PlayerView struct {
+	Name                      string
+	IsViewer                  bool
+	Bot                       string
+	HandSize                  int
+	Points                    int
+	ProspectTokens            int
+	ScorePile                 int
+	ProspectAndPresentChips   int
+	CanProspectAndPresent     bool
+	HasDecidedHandOrientation bool
+	IsReady                   bool
+	RoundResults              []RoundResult
+	// Revealed lists the cards in the player's hand that everybody saw them
+	// take from the table.
+	Revealed []RevealedCard
+}
+
+// RevealedCard is a card at a known position in a player's hand.
+type RevealedCard struct {
+	Position int
+	Card     Card
+}
+
+// ViewFor projects the Game as seen by the given player. Unknown player ids
+// (such as spectators) receive only public information.
+func (g *Game) ViewFor(playerId string) *View {
+	v := g.view(playerId)
+	if p := v.Player(); p != nil && g.Training && !p.HasDecidedHandOrientation {
+		if advice, ok := g.givenAdvice(playerId); ok {
+			v.OrientationAdvice = &advice
+		}
+	}
+	return v
+}
+
+// view is ViewFor without the OrientationAdvice, which is itself worked out
+// from the view.
+func (g *Game) view(playerId string) *View {
+	v := &View{
+		Id:                  g.Id,
+		Version:             g.Version(),
+		Mode:                g.Mode,
+		Phase:               g.Phase,
+		Round:               g.Round,
+		CurrentPlayer:       g.CurrentPlayer,
+		LastPlayerToPresent: g.LastPlayerToPresent,
+		Presentation:        slices.Clone(g.Presentation),
+		Discarded:           slices.Clone(g.Discarded),
+		Players:             make([]PlayerView, len(g.Players)),
+		Viewer:              -1,
+		Training:            g.Training,
+		isFull:              g.IsFull(),
+		hasEnoughPlayers:    g.HasEnoughPlayers(),
+	}
+	for i := range g.Players {
+		p := &g.Players[i]
+		v.Players[i] = PlayerView{
+			Name:                      p.Name,
+			IsViewer:                  p.Id == playerId,
+			Bot:                       p.Bot,
+			HandSize:                  len(p.Hand),
+			Points:                    p.Points,
+			ProspectTokens:            p.ProspectTokens,
+			ScorePile:                 p.ScorePile,
+			ProspectAndPresentChips:   p.ProspectAndPresentChips,
+			CanProspectAndPresent:     p.CanProspectAndPresent,
+			HasDecidedHandOrientation: p.HasDecidedHandOrientation,
+			IsReady:                   p.IsReady,
+			RoundResults:              slices.Clone(p.RoundResults),
+		}
+		for j, revealed := range p.Revealed {
+			if revealed {
+				v.Players[i].Revealed = append(v.Players[i].Revealed, RevealedCard{Position: j, Card: p.Hand[j]})
+			}
+		}
+		if p.Id == playerId {
+			v.Viewer = i
+			v.Hand = slices.Clone(p.Hand)
+			v.LegalActions = g.LegalActions(playerId)
+		}
+	}
+	return v
+}
+
+// Player returns the viewer's PlayerView, or nil if the viewer is not seated.
+func (v *View) Player() *PlayerView {
+	if v.Viewer < 0 {
+		return nil
+	}
+	return &v.Players[v.Viewer]
+}
+
+func (v *View) IsLobby() bool {
+	return v.Phase == PhaseLobby
+}
+
+func (v *View) IsRoundOver() bool {
+	return v.Phase == PhaseRoundOver
+}
+
+func (v *View) IsGameOver() bool {
+	return v.Phase == PhaseGameOver
+}
+
+func (v *View) IsFull() bool {
+	return v.isFull
+}
+
+func (v *View) HasEnoughPlayers() bool {
+	return v.hasEnoughPlayers
+}
+
+// IsYourTurn reports whether the viewer is the player who must act next.
+func (v *View) IsYourTurn() bool {
+	return v.Viewer >= 0 && v.Viewer == v.CurrentPlayer &&
+		(v.Phase == PhasePlaying || v.Phase == PhaseAwaitingPresentOrPass)
+}
+
+// IsDecidingPresent reports whether the viewer has just prospected and must
+// now decide whether to present or pass.
+func (v *View) IsDecidingPresent() bool {
+	return v.IsYourTurn() && v.Phase == PhaseAwaitingPresentOrPass
+}
+
+// PlayablePresentations lists the presentations from the viewer's hand that
+// would beat the current presentation, from least to most valuable.
+func (v *View) PlayablePresentations() [][]Card {
+	return getPlayablePresentations(v.Hand, v.Presentation)
+}
+
+// Standings is Game.Standings, for the players in the View.
+func (v *View) Standings() []*PlayerView {
+	return standings(v.Players, func(p *PlayerView) int { return p.Points })
+}
+
+// Winners is Game.Winners, for the players in the View.
+func (v *View) Winners() []*PlayerView {
+	return winners(v.Players, func(p *PlayerView) int { return p.Points })
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestGame_ViewFor(t *testing.T) {
+	g := &Game{Rand: rand.New(rand.NewPCG(1, 2))}
+	for i := range 3 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	err := g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+
+	t.Run("player", func(t *testing.T) {
+		v := g.ViewFor("1")
+		if v.Viewer != 1 || v.Player() != &v.Players[1] || !v.Players[1].IsViewer {
+			t.Fatalf("expected viewer to be player 1, got %d", v.Viewer)
+		}
+		assertCardSlicesEqual(t, g.Players[1].Hand, v.Hand)
+		for i := range v.Players {
+			if v.Players[i].HandSize != len(g.Players[i].Hand) {
+				t.Fatalf("expected player %d hand size %d, got %d", i, len(g.Players[i].Hand), v.Players[i].HandSize)
+			}
+		}
+		if v.Version != g.Version() {
+			t.Fatalf("expected version %d, got %d", g.Version(), v.Version)
+		}
+		if len(v.LegalActions) != 2 {
+			t.Fatalf("expected 2 legal actions, got %v", v.LegalActions)
+		}
+
+		// The view must not share memory with the game
+		v.Hand[0] = Card{0, 0}
+		if g.Players[1].Hand[0] == (Card{0, 0}) {
+			t.Fatal("expected view hand to be a copy")
+		}
+
+		// A view does not change with the game
+		err := g.DecideHandOrientation("0", false)
+		if err != nil {
+			t.Fatalf("unexpected error deciding orientation: %v", err)
+		}
+		if v.Version >= g.Version() {
+			t.Fatalf("expected game version %d to increase past %d", g.Version(), v.Version)
+		}
+	})
+
+	t.Run("spectator", func(t *testing.T) {
+		v := g.ViewFor("spectator")
+		if v.Viewer != -1 || v.Player() != nil {
+			t.Fatalf("expected no viewer, got %d", v.Viewer)
+		}
+		if v.Hand != nil || v.LegalActions != nil {
+			t.Fatalf("expected spectator to see no hand or actions, got %v, %v", v.Hand, v.LegalActions)
+		}
+		if v.IsYourTurn() {
+			t.Fatal("expected spectator to never have a turn")
+		}
+	})
+
+	t.Run("cards seen on the table", func(t *testing.T) {
+		g := &Game{
+			Phase:               PhasePlaying,
+			Round:               1,
+			CurrentPlayer:       1,
+			LastPlayerToPresent: 0,
+			Presentation:        []Card{{1, 2}, {2, 3}},
+			Players: []Player{
+				{Id: "0", Hand: []Card{{5, 6}}, HasDecidedHandOrientation: true},
+				{Id: "1", Hand: []Card{{7, 8}, {9, 10}}, HasDecidedHandOrientation: true},
+				{Id: "2", Hand: []Card{{6, 7}, {8, 9}}, HasDecidedHandOrientation: true},
+			},
+		}
+		err := g.Prospect("1", false, true, 1)
+		if err != nil {
+			t.Fatalf("unexpected error prospecting: %v", err)
+		}
+		err = g.Present("2", 0, 1)
+		if err != nil {
+			t.Fatalf("unexpected error presenting: %v", err)
+		}
+
+		v := g.ViewFor("0")
+		revealed := v.Players[1].Revealed
+		if len(revealed) != 1 || revealed[0] != (RevealedCard{Position: 1, Card: Card{3, 2}}) {
+			t.Fatalf("expected player 1 to be seen holding {3 2} at position 1, got %v", revealed)
+		}
+		assertCardSlicesEqual(t, []Card{{1, 2}}, v.Discarded)
+	})
+}
@@ -45,6 +45,7 @@ func (s *server) withGameRoom(next http.Handler) http.Handler {
 			}
 			return
 		}
+		gr.Touch()
 		r = withGameRoomContext(r, gr)
 
 		playerId := gr.EnsurePlayer(getPlayerIdCookie(r))
@@ -0,0 +1,51 @@
+package room
+
+import "github.com/djcrock/prospect/internal/game"
+
+// advice is orientation advice worked out for a player, on its way back to
+// the actor.
+type advice struct {
+	round    int
+	playerId string
+	advice   game.OrientationAdvice
+}
+
+// updateAdvice starts working out orientation advice for every human player
+// in a training game who is yet to orient their hand and has none. The advice
+// takes a while, so it is worked out off the actor and then handed back to it
+// through r.advised. It must only be called by the actor.
+func (r *Room) updateAdvice() {
+	g := r.Game
+	if !g.Training || g.Phase != game.PhaseOrienting {
+		return
+	}
+	for i := range g.Players {
+		p := &g.Players[i]
+		if p.Bot != "" || p.HasDecidedHandOrientation || g.HasOrientationAdvice(p.Id) || r.advising[p.Id] == g.Round {
+			continue
+		}
+		advise, err := g.OrientationAdvisor(p.Id)
+		if err != nil {
+			continue
+		}
+		r.advising[p.Id] = g.Round
+		round, playerId := g.Round, p.Id
+		go func() {
+			a := advice{round: round, playerId: playerId, advice: advise()}
+			select {
+			case r.advised <- a:
+			case <-r.ctx.Done():
+			}
+		}()
+	}
+}
+
+// giveAdvice shows the players the advice worked out for them. It must only
+// be called by the actor.
+func (r *Room) giveAdvice(a advice) {
+	delete(r.advising, a.playerId)
+	r.Mu.Lock()
+	r.Game.GiveOrientationAdvice(a.round, a.playerId, a.advice)
+	r.Mu.Unlock()
+	r.publish(r.listeners)
+}
@@ -0,0 +1,65 @@
+package room
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+func TestRoom_advice(t *testing.T) {
+	r := NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	defer r.Close()
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		playerId string
+		cmd      Command
+	}{
+		{"a", Join{Name: "A"}},
+		{"a", AddBot{}},
+		{"b", Join{Name: "B"}},
+		{"a", SetTraining{Enabled: true}},
+	} {
+		_, err := r.Submit(ctx, c.playerId, c.cmd)
+		if err != nil {
+			t.Fatalf("unexpected error submitting %T: %v", c.cmd, err)
+		}
+	}
+
+	renderAdvice := func(g *game.Game, viewerId string) []byte {
+		if g.ViewFor(viewerId).OrientationAdvice != nil {
+			return []byte("advised")
+		}
+		return []byte("waiting")
+	}
+	humans := []<-chan Update{r.Listen(ctx, "a", renderAdvice), r.Listen(ctx, "b", renderAdvice)}
+	for _, updates := range humans {
+		<-updates
+	}
+
+	// Rendering does not wait for the advice, which follows once it has been
+	// worked out
+	_, err := r.Submit(ctx, "a", Start{})
+	if err != nil {
+		t.Fatalf("unexpected error starting: %v", err)
+	}
+	deadline := time.After(10 * time.Second)
+	for _, updates := range humans {
+		for advised := false; !advised; {
+			select {
+			case u := <-updates:
+				advised = string(u.State) == "advised"
+			case <-deadline:
+				t.Fatal("timed out waiting for advice")
+			}
+		}
+	}
+
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.Game.HasOrientationAdvice(r.Game.Players[1].Id) {
+		t.Error("expected no advice for the bot")
+	}
+}
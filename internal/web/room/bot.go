@@ -0,0 +1,161 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/djcrock/prospect/internal/bot"
+	"github.com/djcrock/prospect/internal/game"
+	"github.com/djcrock/prospect/internal/util"
+)
+
+// botIdPrefix starts the player ids of bots. Ids handed out to people are
+// only letters, so the two never collide.
+const botIdPrefix = "bot-"
+
+// AddBot seats a bot in the lobby, which plays using the named strategy. Only
+// seated players may add bots.
+type AddBot struct {
+	Strategy string
+}
+
+// RemoveBot unseats the named bot from the lobby. Only seated players may
+// remove bots.
+type RemoveBot struct {
+	Name string
+}
+
+func (c AddBot) execute(g *game.Game, playerId string) error {
+	if g.GetPlayerById(playerId) == nil {
+		return game.ErrPlayerNotFound
+	}
+	strategy := c.Strategy
+	if strategy == "" {
+		strategy = bot.DefaultStrategy
+	}
+	if _, err := bot.New(strategy); err != nil {
+		return err
+	}
+
+	// Bots are numbered from 1, filling any gaps left by removed bots
+	var name string
+	for n := 1; ; n++ {
+		name = fmt.Sprintf("Bot %d", n)
+		if findBot(g, name) == nil {
+			break
+		}
+	}
+	for range randomIdRetries {
+		id := botIdPrefix + util.RandomString(playerIdLength)
+		if g.GetPlayerById(id) == nil {
+			return g.AddBot(id, name, strategy)
+		}
+	}
+	return fmt.Errorf("failed to generate a unique bot id after %d iterations", randomIdRetries)
+}
+
+func (c RemoveBot) execute(g *game.Game, playerId string) error {
+	if g.GetPlayerById(playerId) == nil {
+		return game.ErrPlayerNotFound
+	}
+	p := findBot(g, c.Name)
+	if p == nil {
+		return game.ErrPlayerNotFound
+	}
+	if !g.RemovePlayer(p.Id) {
+		return game.ErrGameStarted
+	}
+	return nil
+}
+
+// findBot returns the bot seated in the game with the given name, or nil if
+// there is none.
+func findBot(g *game.Game, name string) *game.Player {
+	for i := range g.Players {
+		if g.Players[i].Bot != "" && g.Players[i].Name == name {
+			return &g.Players[i]
+		}
+	}
+	return nil
+}
+
+// updateBots starts a goroutine for every bot seated in the game that does not
+// yet have one, and stops those of bots no longer seated. The rest are woken
+// to look at the game, which may have changed. It must only be called by the
+// actor.
+func (r *Room) updateBots() {
+	for playerId, wake := range r.bots {
+		if r.Game.GetPlayerById(playerId) == nil {
+			close(wake)
+			delete(r.bots, playerId)
+			continue
+		}
+		select {
+		case wake <- struct{}{}:
+		default:
+			// The bot has yet to look at an earlier change
+		}
+	}
+	for i := range r.Game.Players {
+		p := &r.Game.Players[i]
+		if _, ok := r.bots[p.Id]; p.Bot == "" || ok {
+			continue
+		}
+		b, err := bot.New(p.Bot)
+		if err != nil {
+			log.Printf("failed to start bot %s in game %s: %v", p.Name, r.Game.Id, err)
+			continue
+		}
+		wake := make(chan struct{}, 1)
+		wake <- struct{}{}
+		r.bots[p.Id] = wake
+		go r.runBot(p.Id, b, wake)
+	}
+}
+
+// runBot plays for a bot until the room is closed or the bot is removed. It
+// looks at the game whenever it is woken, and submits the bot's moves just as
+// a human's are, after waiting for the room's think delay. Bots are not
+// listeners, so they do not keep the room from being reaped.
+func (r *Room) runBot(playerId string, b bot.Bot, wake <-chan struct{}) {
+	for {
+		select {
+		case _, ok := <-wake:
+			if !ok {
+				return
+			}
+		case <-r.ctx.Done():
+			return
+		}
+
+		r.Mu.RLock()
+		v := r.Game.ViewFor(playerId)
+		r.Mu.RUnlock()
+		if len(v.LegalActions) == 0 {
+			continue
+		}
+
+		select {
+		case <-time.After(r.botDelay):
+		case <-r.ctx.Done():
+			return
+		}
+
+		action := b.Choose(v)
+		_, err := r.Submit(r.ctx, playerId, AtVersion{Version: v.Version, Command: Play{Action: action}})
+		if err == nil || errors.Is(err, game.ErrStaleVersion) || errors.Is(err, ErrRoomClosed) || r.ctx.Err() != nil {
+			// A stale bot is woken by the newer state
+			continue
+		}
+
+		// The game would stall if the bot never moved, so fall back to any
+		// legal move.
+		log.Printf("bot %s in game %s chose %#v: %v", playerId, r.Game.Id, action, err)
+		_, err = r.Submit(r.ctx, playerId, AtVersion{Version: v.Version, Command: Play{Action: v.LegalActions[0]}})
+		if err != nil && !errors.Is(err, game.ErrStaleVersion) && r.ctx.Err() == nil {
+			log.Printf("bot %s in game %s failed to move: %v", playerId, r.Game.Id, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/bot"
+	"github.com/djcrock/prospect/internal/game"
+)
+
+func TestRoom_bots(t *testing.T) {
+	r := NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	defer r.Close()
+	ctx := context.Background()
+
+	_, err := r.Submit(ctx, "bot", AddBot{})
+	if !errors.Is(err, game.ErrPlayerNotFound) {
+		t.Errorf("got error %v adding a bot as a spectator; want %v", err, game.ErrPlayerNotFound)
+	}
+	_, err = r.Submit(ctx, "human", Join{Name: "Human"})
+	if err != nil {
+		t.Fatalf("unexpected error joining: %v", err)
+	}
+	_, err = r.Submit(ctx, "human", AddBot{Strategy: "nonsense"})
+	if !errors.Is(err, bot.ErrUnknownStrategy) {
+		t.Errorf("got error %v adding a bot with an unknown strategy; want %v", err, bot.ErrUnknownStrategy)
+	}
+	for range 2 {
+		_, err = r.Submit(ctx, "human", AddBot{})
+		if err != nil {
+			t.Fatalf("unexpected error adding bot: %v", err)
+		}
+	}
+
+	// Removed bots leave a gap that the next bot fills
+	_, err = r.Submit(ctx, "bot", RemoveBot{Name: "Bot 1"})
+	if !errors.Is(err, game.ErrPlayerNotFound) {
+		t.Errorf("got error %v removing a bot as a spectator; want %v", err, game.ErrPlayerNotFound)
+	}
+	_, err = r.Submit(ctx, "human", RemoveBot{Name: "Human"})
+	if !errors.Is(err, game.ErrPlayerNotFound) {
+		t.Errorf("got error %v removing a human; want %v", err, game.ErrPlayerNotFound)
+	}
+	_, err = r.Submit(ctx, "human", RemoveBot{Name: "Bot 1"})
+	if err != nil {
+		t.Fatalf("unexpected error removing bot: %v", err)
+	}
+	_, err = r.Submit(ctx, "human", AddBot{})
+	if err != nil {
+		t.Fatalf("unexpected error adding bot: %v", err)
+	}
+
+	_, err = r.Submit(ctx, "human", Start{})
+	if err != nil {
+		t.Fatalf("unexpected error starting: %v", err)
+	}
+	_, err = r.Submit(ctx, "human", RemoveBot{Name: "Bot 1"})
+	if !errors.Is(err, game.ErrGameStarted) {
+		t.Errorf("got error %v removing a bot from a started game; want %v", err, game.ErrGameStarted)
+	}
+
+	// The bots play their own moves while the human plays theirs
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		r.Mu.RLock()
+		v := r.Game.ViewFor("human")
+		r.Mu.RUnlock()
+		if v.IsGameOver() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("game did not finish; stuck in phase %v", v.Phase)
+		}
+		if len(v.LegalActions) == 0 {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		_, err = r.Submit(ctx, "human", AtVersion{Version: v.Version, Command: Play{Action: v.LegalActions[0]}})
+		if err != nil && !errors.Is(err, game.ErrStaleVersion) {
+			t.Fatalf("unexpected error playing %#v: %v", v.LegalActions[0], err)
+		}
+	}
+
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.Game.Players[1].Name != "Bot 2" || r.Game.Players[2].Name != "Bot 1" {
+		t.Errorf("got bots named %q and %q; want Bot 2 and Bot 1", r.Game.Players[1].Name, r.Game.Players[2].Name)
+	}
+	for _, p := range r.Game.Players[1:] {
+		if !strings.HasPrefix(p.Id, botIdPrefix) {
+			t.Errorf("got bot id %q; want it to start with %q", p.Id, botIdPrefix)
+		}
+	}
+}
+
+func TestRoom_RemoveBot(t *testing.T) {
+	r := NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	defer r.Close()
+	ctx := context.Background()
+
+	for _, cmd := range []Command{Join{Name: "A"}, AddBot{}} {
+		_, err := r.Submit(ctx, "a", cmd)
+		if err != nil {
+			t.Fatalf("unexpected error submitting %T: %v", cmd, err)
+		}
+	}
+	spectator := r.Listen(ctx, "", renderPlayers)
+	if u := <-spectator; string(u.State) != "ABot 1" {
+		t.Fatalf("got %q; want the spectator view", u.State)
+	}
+
+	// Spectators are sent the game, and nothing meant for the removed bot
+	_, err := r.Submit(ctx, "a", RemoveBot{Name: "Bot 1"})
+	if err != nil {
+		t.Fatalf("unexpected error removing bot: %v", err)
+	}
+	select {
+	case u := <-spectator:
+		if string(u.State) != "A" || u.Version != 3 {
+			t.Errorf("got %q at version %d; want the spectator view at version 3", u.State, u.Version)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an update")
+	}
+}
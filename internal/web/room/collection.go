@@ -1,25 +1,49 @@
 package room
 
 import (
+	"errors"
 	"fmt"
 	"github.com/djcrock/prospect/internal/game"
 	"github.com/djcrock/prospect/internal/util"
 	"math/rand/v2"
 	"sync"
+	"time"
 )
 
 type Collection struct {
-	mu    sync.RWMutex
-	rooms map[string]*Room
+	mu       sync.RWMutex
+	rooms    map[string]*Room
+	store    Store
+	botDelay time.Duration
 }
 
-func NewCollection() *Collection {
+// NewCollection creates an empty Collection, whose games are persisted to
+// store. A nil store persists nothing. Bots seated in the games wait for
+// botDelay before each move, so that humans can follow along.
+func NewCollection(store Store, botDelay time.Duration) *Collection {
+	if store == nil {
+		store = NopStore{}
+	}
 	return &Collection{
-		rooms: make(map[string]*Room),
+		rooms:    make(map[string]*Room),
+		store:    store,
+		botDelay: botDelay,
+	}
+}
+
+// Load creates a room for every game in the Collection's Store.
+func (c *Collection) Load() error {
+	games, err := c.store.LoadAll()
+	if err != nil {
+		return err
+	}
+	for _, g := range games {
+		c.GetRoomForGame(g)
 	}
+	return nil
 }
 
-func (c *Collection) NewRoom() *Room {
+func (c *Collection) NewRoom(mode game.Mode) *Room {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -27,13 +51,9 @@ func (c *Collection) NewRoom() *Room {
 		gameId := util.RandomString(gameIdLength)
 		_, ok := c.rooms[gameId]
 		if !ok {
-			g := &game.Game{
-				Id:   gameId,
-				Rand: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
-			}
-			gameRoom := NewRoom(g)
+			g := game.New(gameId, mode, [2]uint64{rand.Uint64(), rand.Uint64()})
+			gameRoom := newRoom(g, c.store, c.botDelay)
 			c.rooms[gameId] = gameRoom
-			gameRoom.start()
 			return gameRoom
 		}
 	}
@@ -46,10 +66,19 @@ func (c *Collection) GetRoom(gameId string) *Room {
 	return c.rooms[gameId]
 }
 
-func (c *Collection) RemoveRoom(gameId string) {
+// RemoveRoom removes a room and deletes its game from the Store.
+func (c *Collection) RemoveRoom(gameId string) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	room, ok := c.rooms[gameId]
 	delete(c.rooms, gameId)
+	c.mu.Unlock()
+
+	// Closing waits for the room's actor, so other rooms must remain
+	// reachable meanwhile.
+	if ok {
+		room.Close()
+	}
+	return c.store.Delete(gameId)
 }
 
 func (c *Collection) GetRoomForGame(game *game.Game) *Room {
@@ -70,8 +99,31 @@ func (c *Collection) GetRoomForGame(game *game.Game) *Room {
 		return room
 	}
 
-	room = NewRoom(game)
+	room = newRoom(game, c.store, c.botDelay)
 	c.rooms[gameId] = room
 
 	return room
 }
+
+// Shutdown tells the listeners of every room that the server is shutting down.
+func (c *Collection) Shutdown() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, room := range c.rooms {
+		room.Shutdown()
+	}
+}
+
+// SaveAll persists the game of every room.
+func (c *Collection) SaveAll() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var errs []error
+	for _, room := range c.rooms {
+		err := room.Save()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("game %s: %w", room.Game.Id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package room
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+func TestCollection_RemoveRoom(t *testing.T) {
+	c := NewCollection(nil, 0)
+	busy := c.NewRoom(game.ModeStandard)
+	other := c.NewRoom(game.ModeStandard)
+
+	// Hold up the busy room's actor in the middle of a command
+	busy.Mu.RLock()
+	go func() {
+		_, _ = busy.Submit(context.Background(), "a", Join{Name: "A"})
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	removed := make(chan error)
+	go func() {
+		removed <- c.RemoveRoom(busy.Game.Id)
+	}()
+
+	// Other rooms are reachable while the busy room closes
+	found := make(chan *Room)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		found <- c.GetRoom(other.Game.Id)
+	}()
+	select {
+	case r := <-found:
+		if r != other {
+			t.Errorf("got room %p; want %p", r, other)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("collection was locked while a room closed")
+	}
+
+	busy.Mu.RUnlock()
+	select {
+	case err := <-removed:
+		if err != nil {
+			t.Errorf("unexpected error removing room: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("room was not removed")
+	}
+	if c.GetRoom(busy.Game.Id) != nil {
+		t.Error("removed room is still in the collection")
+	}
+}
@@ -0,0 +1,168 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// ErrRoomClosed is returned for commands submitted to a room that has been
+// closed.
+var ErrRoomClosed = errors.New("this game is no longer available")
+
+// Command is a change to a room's game made on behalf of a player. It is one
+// of Join, AddBot, RemoveBot, Leave, SetTraining, Start or Play, optionally
+// wrapped in AtVersion.
+type Command interface {
+	execute(g *game.Game, playerId string) error
+}
+
+// Join seats the player in the lobby.
+type Join struct {
+	Name string
+}
+
+// Leave removes the player from the lobby.
+type Leave struct{}
+
+// SetTraining turns the game's training mode on or off.
+type SetTraining struct {
+	Enabled bool
+}
+
+// Start deals the first round.
+type Start struct{}
+
+// Play makes a move in the game.
+type Play struct {
+	Action game.Action
+}
+
+// AtVersion applies Command only if the game is still at Version, so that
+// a player cannot act on a state they have not seen. Only moves that depend
+// on the table or the player's hand are checked: the others, such as orienting
+// or joining, mean the same whatever else has happened, and other players may
+// well make them at the same moment.
+type AtVersion struct {
+	Version int
+	Command Command
+}
+
+func (c Join) execute(g *game.Game, playerId string) error {
+	return g.AddPlayer(playerId, c.Name)
+}
+
+func (c Leave) execute(g *game.Game, playerId string) error {
+	if !g.RemovePlayer(playerId) {
+		if g.GetPlayerById(playerId) == nil {
+			return game.ErrPlayerNotFound
+		}
+		return game.ErrGameStarted
+	}
+	return nil
+}
+
+func (c SetTraining) execute(g *game.Game, playerId string) error {
+	return g.SetTraining(playerId, c.Enabled)
+}
+
+func (c Start) execute(g *game.Game, playerId string) error {
+	if g.GetPlayerById(playerId) == nil {
+		return game.ErrPlayerNotFound
+	}
+	return g.Start()
+}
+
+func (c Play) execute(g *game.Game, playerId string) error {
+	return g.Apply(playerId, c.Action)
+}
+
+func (c AtVersion) execute(g *game.Game, playerId string) error {
+	if g.Version() != c.Version && dependsOnTable(c.Command) {
+		return game.ErrStaleVersion
+	}
+	return c.Command.execute(g, playerId)
+}
+
+// dependsOnTable reports whether a command refers to positions in the
+// presentation or the player's hand, which may have changed since it was made.
+func dependsOnTable(cmd Command) bool {
+	play, ok := cmd.(Play)
+	if !ok {
+		return false
+	}
+	switch play.Action.(type) {
+	case game.PresentAction, game.ProspectAction, game.PassAction:
+		return true
+	}
+	return false
+}
+
+// commandQueueSize is how many commands may be queued for a room before
+// Submit blocks.
+const commandQueueSize = 16
+
+type command struct {
+	playerId string
+	cmd      Command
+	result   chan commandResult
+}
+
+type commandResult struct {
+	version int
+	err     error
+}
+
+// Submit queues a command for the room's actor and waits for it to be
+// applied. Commands are applied one at a time, in the order they are
+// submitted. It returns the game's Version after the command was applied.
+func (r *Room) Submit(ctx context.Context, playerId string, cmd Command) (int, error) {
+	c := command{
+		playerId: playerId,
+		cmd:      cmd,
+		// Buffered so that the actor never waits for a submitter that has
+		// given up.
+		result: make(chan commandResult, 1),
+	}
+	select {
+	case r.commands <- c:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-r.ctx.Done():
+		return 0, ErrRoomClosed
+	}
+
+	select {
+	case res := <-c.result:
+		return res.version, res.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-r.ctx.Done():
+		return 0, ErrRoomClosed
+	}
+}
+
+// execute applies a command to the game, persists the result and notifies
+// the room's listeners. It must only be called by the actor.
+func (r *Room) execute(c command) commandResult {
+	r.Mu.Lock()
+	err := c.cmd.execute(r.Game, c.playerId)
+	if err != nil {
+		r.Mu.Unlock()
+		return commandResult{version: r.Game.Version(), err: err}
+	}
+	version := r.Game.Version()
+	r.version.Store(int64(version))
+	r.Mu.Unlock()
+
+	err = r.Save()
+	if err != nil {
+		log.Printf("failed to save game %s: %v", r.Game.Id, err)
+	}
+	r.updateBots()
+	r.updateAdvice()
+	r.publish(r.listeners)
+	return commandResult{version: version}
+}
@@ -0,0 +1,92 @@
+package room
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// IdleTimeouts are how long rooms may go unused before they are removed,
+// depending on the state of their game. A zero timeout never expires.
+type IdleTimeouts struct {
+	// Lobby applies to games that have not started.
+	Lobby time.Duration
+	// Active applies to games in progress.
+	Active time.Duration
+	// Finished applies to games that are over.
+	Finished time.Duration
+}
+
+func (t IdleTimeouts) forPhase(phase game.Phase) time.Duration {
+	switch phase {
+	case game.PhaseLobby:
+		return t.Lobby
+	case game.PhaseGameOver:
+		return t.Finished
+	default:
+		return t.Active
+	}
+}
+
+// Reap removes every room that has been idle for longer than its timeout, and
+// returns the ids of the games removed. Rooms that somebody is watching are
+// never idle.
+func (c *Collection) Reap(timeouts IdleTimeouts, now time.Time) ([]string, error) {
+	c.mu.RLock()
+	rooms := make([]*Room, 0, len(c.rooms))
+	for _, room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	c.mu.RUnlock()
+
+	var reaped []string
+	for _, room := range rooms {
+		lastActive := room.LastActive()
+		room.Mu.RLock()
+		gameId := room.Game.Id
+		timeout := timeouts.forPhase(room.Game.Phase)
+		room.Mu.RUnlock()
+		if timeout <= 0 || now.Sub(lastActive) <= timeout || room.IsWatched() {
+			continue
+		}
+
+		c.mu.Lock()
+		// Skip the room if it was used or replaced while the lock was released
+		if c.rooms[gameId] != room || !room.LastActive().Equal(lastActive) || room.IsWatched() {
+			c.mu.Unlock()
+			continue
+		}
+		delete(c.rooms, gameId)
+		c.mu.Unlock()
+
+		room.Close()
+		reaped = append(reaped, gameId)
+		err := c.store.Delete(gameId)
+		if err != nil {
+			return reaped, err
+		}
+	}
+	return reaped, nil
+}
+
+// RunJanitor reaps idle rooms every interval until ctx is done.
+func (c *Collection) RunJanitor(ctx context.Context, interval time.Duration, timeouts IdleTimeouts, logger *log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			reaped, err := c.Reap(timeouts, now)
+			if len(reaped) > 0 {
+				logger.Printf("removed %d idle games: %v", len(reaped), reaped)
+			}
+			if err != nil {
+				logger.Printf("failed to remove idle game: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
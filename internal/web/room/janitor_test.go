@@ -0,0 +1,91 @@
+package room
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+func TestCollection_Reap(t *testing.T) {
+	c := NewCollection(nil, 0)
+	lobby := c.NewRoom(game.ModeStandard)
+	finished := c.NewRoom(game.ModeStandard)
+	finished.Game.Phase = game.PhaseGameOver
+
+	timeouts := IdleTimeouts{Lobby: time.Hour, Finished: time.Minute}
+	now := time.Now()
+
+	reaped, err := c.Reap(timeouts, now)
+	if err != nil || len(reaped) != 0 {
+		t.Errorf("got %v, %v reaping fresh rooms; want none", reaped, err)
+	}
+
+	reaped, err = c.Reap(timeouts, now.Add(10*time.Minute))
+	if err != nil || len(reaped) != 1 || reaped[0] != finished.Game.Id {
+		t.Errorf("got %v, %v; want only the finished game reaped", reaped, err)
+	}
+	if c.GetRoom(finished.Game.Id) != nil {
+		t.Error("reaped room is still in the collection")
+	}
+	select {
+	case <-finished.Done():
+	default:
+		t.Error("reaped room was not closed")
+	}
+	// Listening to a closed room must not block
+	finished.Listen(context.Background(), "", renderPlayers)
+
+	// Rooms are in use for as long as somebody watches them
+	ctx, cancel := context.WithCancel(context.Background())
+	lobby.Listen(ctx, "", renderPlayers)
+	for !lobby.IsWatched() {
+		time.Sleep(time.Millisecond)
+	}
+	reaped, err = c.Reap(timeouts, time.Now().Add(2*time.Hour))
+	if err != nil || len(reaped) != 0 {
+		t.Errorf("got %v, %v reaping a watched lobby; want none", reaped, err)
+	}
+	cancel()
+	for lobby.IsWatched() {
+		time.Sleep(time.Millisecond)
+	}
+
+	lobby.Touch()
+	reaped, err = c.Reap(timeouts, time.Now().Add(30*time.Minute))
+	if err != nil || len(reaped) != 0 {
+		t.Errorf("got %v, %v reaping a recently used lobby; want none", reaped, err)
+	}
+	reaped, err = c.Reap(timeouts, time.Now().Add(2*time.Hour))
+	if err != nil || len(reaped) != 1 || reaped[0] != lobby.Game.Id {
+		t.Errorf("got %v, %v; want the idle lobby reaped", reaped, err)
+	}
+
+	// Bots do not count as watching a room
+	ctx = context.Background()
+	abandoned := c.NewRoom(game.ModeStandard)
+	for _, cmd := range []Command{Join{Name: "Human"}, AddBot{}, Leave{}} {
+		_, err := abandoned.Submit(ctx, "human", cmd)
+		if err != nil {
+			t.Fatalf("unexpected error submitting %T: %v", cmd, err)
+		}
+	}
+	// Give the bot time to start up, as it would if it were a listener
+	time.Sleep(10 * time.Millisecond)
+	if abandoned.IsWatched() {
+		t.Error("room with only a bot is watched")
+	}
+	reaped, err = c.Reap(timeouts, time.Now().Add(2*time.Hour))
+	if err != nil || len(reaped) != 1 || reaped[0] != abandoned.Game.Id {
+		t.Errorf("got %v, %v; want the lobby left to a bot reaped", reaped, err)
+	}
+
+	// A zero timeout never expires
+	active := c.NewRoom(game.ModeStandard)
+	active.Game.Phase = game.PhasePlaying
+	reaped, err = c.Reap(timeouts, time.Now().Add(1000*time.Hour))
+	if err != nil || len(reaped) != 0 {
+		t.Errorf("got %v, %v reaping with no active timeout; want none", reaped, err)
+	}
+}
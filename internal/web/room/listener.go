@@ -0,0 +1,114 @@
+package room
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// Update is the state of a room's game, rendered for one viewer.
+type Update struct {
+	Version int
+	// State is nil if the viewer was last sent the same rendering, so that
+	// only the new Version needs delivering.
+	State []byte
+}
+
+// Renderer renders a game as seen by a viewer. Viewers who are not seated in
+// the game are rendered with an empty viewerId, so that all spectators share a
+// rendering.
+type Renderer func(g *game.Game, viewerId string) []byte
+
+type listener struct {
+	viewerId string
+	render   Renderer
+	updates  chan Update
+	// last is the state most recently sent to the listener, and is nil until
+	// the first is sent.
+	last []byte
+}
+
+// send delivers an Update, replacing any the listener has not yet received.
+// A replaced State is carried over if the new Update leaves it unchanged. It
+// must only be called by the actor, which is the only sender.
+func (l *listener) send(u Update) {
+	select {
+	case l.updates <- u:
+	default:
+		select {
+		case pending := <-l.updates:
+			if u.State == nil {
+				u.State = pending.State
+			}
+		default:
+		}
+		l.updates <- u
+	}
+}
+
+// publish renders the game once for each distinct viewer and sends it to every
+// listener, leaving out the State for those whose view has not changed. It
+// must only be called by the actor.
+func (r *Room) publish(listeners map[*listener]bool) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+
+	version := r.Game.Version()
+	rendered := make(map[string][]byte)
+	for l := range listeners {
+		viewerId := l.viewerId
+		if r.Game.GetPlayerById(viewerId) == nil {
+			viewerId = ""
+		}
+		state, ok := rendered[viewerId]
+		if !ok {
+			state = l.render(r.Game, viewerId)
+			if state == nil {
+				state = []byte{}
+			}
+			rendered[viewerId] = state
+		}
+		if l.last != nil && bytes.Equal(state, l.last) {
+			// Nothing this listener can see has changed
+			l.send(Update{Version: version})
+			continue
+		}
+		l.last = state
+		l.send(Update{Version: version, State: state})
+	}
+}
+
+// Listen returns a channel that receives the room's game, rendered for the
+// given viewer, straight away and then whenever the game changes. Updates the
+// listener has not yet received are replaced by newer ones. The channel is
+// closed if the room is closed, and receives nothing further once ctx is
+// done. Renders are shared between listeners with the same viewer, so every
+// listener to a room must use the same Renderer.
+func (r *Room) Listen(ctx context.Context, viewerId string, render Renderer) <-chan Update {
+	l := &listener{
+		viewerId: viewerId,
+		render:   render,
+		updates:  make(chan Update, 1),
+	}
+
+	select {
+	case r.register <- l:
+	case <-r.ctx.Done():
+		close(l.updates)
+		return l.updates
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case r.unregister <- l:
+			case <-r.ctx.Done():
+			}
+		case <-r.ctx.Done():
+		}
+	}()
+
+	return l.updates
+}
@@ -2,55 +2,135 @@ package room
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/djcrock/prospect/internal/game"
 	"github.com/djcrock/prospect/internal/util"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const randomIdRetries = 100
 const gameIdLength = 12
 const playerIdLength = 12
 
-type listener chan<- struct{}
-
+// Room is a game and the clients listening for changes to it. Each Room runs
+// a goroutine, its actor, which applies Commands to the game one at a time and
+// is the sole owner of the listeners. Others talk to the actor through
+// channels, until the Room is closed.
 type Room struct {
-	Mu        sync.RWMutex
-	Game      *game.Game
-	listeners map[listener]bool
+	// Mu guards Game. The actor holds it while applying a Command, so others
+	// need only read lock it.
+	Mu   sync.RWMutex
+	Game *game.Game
 	// TODO: Can this field be removed? What is it actually doing?
 	playerIds map[string]bool
 
-	register   chan listener
-	unregister chan listener
+	// listeners must only be accessed by the actor.
+	listeners  map[*listener]bool
+	register   chan *listener
+	unregister chan *listener
+	commands   chan command
+
+	// bots holds a channel for waking each player with a goroutine playing
+	// for them. It must only be accessed by the actor.
+	bots     map[string]chan struct{}
+	botDelay time.Duration
+
+	// advising records the round for which each player's orientation advice
+	// is being worked out, and advised receives it once it has been. advising
+	// must only be accessed by the actor.
+	advising map[string]int
+	advised  chan advice
+
+	// version is the game's Version, which may be read without holding Mu.
+	version atomic.Int64
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+
+	// lastActive is the time of the last request for the room, in Unix
+	// nanoseconds.
+	lastActive atomic.Int64
+	// listening counts the room's listeners, which may be read without
+	// going through the actor.
+	listening atomic.Int64
+
+	store Store
+	// saveMu serializes saves. saved is the Version of the game last saved,
+	// so that a copy taken earlier never replaces a later one.
+	saveMu sync.Mutex
+	saved  int
 }
 
+// NewRoom creates a Room for a game and starts its actor, which runs until
+// the Room is closed. The game is not persisted, and bots move immediately.
 func NewRoom(game *game.Game) *Room {
+	return newRoom(game, NopStore{}, 0)
+}
+
+func newRoom(game *game.Game, store Store, botDelay time.Duration) *Room {
+	ctx, cancel := context.WithCancel(context.Background())
 	r := &Room{
 		Game:       game,
-		listeners:  make(map[listener]bool),
 		playerIds:  make(map[string]bool),
-		register:   make(chan listener),
-		unregister: make(chan listener),
+		listeners:  make(map[*listener]bool),
+		register:   make(chan *listener),
+		unregister: make(chan *listener),
+		commands:   make(chan command, commandQueueSize),
+		bots:       make(map[string]chan struct{}),
+		botDelay:   botDelay,
+		advising:   make(map[string]int),
+		advised:    make(chan advice),
+		ctx:        ctx,
+		cancel:     cancel,
+		stopped:    make(chan struct{}),
+		shutdown:   make(chan struct{}),
+		store:      store,
 	}
+	r.Touch()
+	r.version.Store(int64(game.Version()))
 	for i := range r.Game.Players {
 		r.EnsurePlayer(r.Game.Players[i].Id)
 	}
 
+	go r.run()
+
 	return r
 }
 
-func (r *Room) start() {
-	go func() {
-		for {
-			select {
-			case l := <-r.register:
-				r.listeners[l] = true
-			case l := <-r.unregister:
+func (r *Room) run() {
+	defer close(r.stopped)
+	r.updateBots()
+	r.updateAdvice()
+	for {
+		select {
+		case l := <-r.register:
+			r.listeners[l] = true
+			r.listening.Store(int64(len(r.listeners)))
+			r.publish(map[*listener]bool{l: true})
+		case l := <-r.unregister:
+			delete(r.listeners, l)
+			r.listening.Store(int64(len(r.listeners)))
+			// The room has been in use for as long as it was watched
+			r.Touch()
+		case c := <-r.commands:
+			c.result <- r.execute(c)
+		case a := <-r.advised:
+			r.giveAdvice(a)
+		case <-r.ctx.Done():
+			for l := range r.listeners {
+				close(l.updates)
 				delete(r.listeners, l)
 			}
+			return
 		}
-	}()
+	}
 }
 
 func (r *Room) EnsurePlayer(existingPlayerId string) string {
@@ -72,28 +152,77 @@ func (r *Room) EnsurePlayer(existingPlayerId string) string {
 	panic(fmt.Sprintf("Failed to generate a unique playerId after %d iterations", randomIdRetries))
 }
 
-func (r *Room) Listen(ctx context.Context) <-chan struct{} {
-	// The notify channel is buffered to allow many listeners to be notified concurrently.
-	// If the notify channel buffer is full, that means the listener still hasn't reacted
-	// to the previous notification and does not need to be notified again.
-	notify := make(chan struct{}, 1)
+// Version returns the Version of the room's game.
+func (r *Room) Version() int {
+	return int(r.version.Load())
+}
+
+// Save persists the room's game. It must not be called with Mu held: Save
+// only holds Mu while it encodes the game, and writes a copy of it after
+// releasing Mu, so that slow storage does not hold up the room.
+func (r *Room) Save() error {
+	r.Mu.RLock()
+	data, err := json.Marshal(r.Game)
+	r.Mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	g := &game.Game{}
+	err = json.Unmarshal(data, g)
+	if err != nil {
+		return err
+	}
+
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+	if g.Version() < r.saved {
+		return nil
+	}
+	err = r.store.Save(g)
+	if err != nil {
+		return err
+	}
+	r.saved = g.Version()
+	return nil
+}
+
+// Touch records that the room is in use, postponing its expiry.
+func (r *Room) Touch() {
+	r.lastActive.Store(time.Now().UnixNano())
+}
+
+// IsWatched reports whether anybody is listening to the room.
+func (r *Room) IsWatched() bool {
+	return r.listening.Load() > 0
+}
 
-	r.register <- notify
+// LastActive returns the time at which the room was last used.
+func (r *Room) LastActive() time.Time {
+	return time.Unix(0, r.lastActive.Load())
+}
 
-	go func() {
-		<-ctx.Done()
-		r.unregister <- notify
-	}()
+// Close stops the room's actor and closes the channels of its listeners. It
+// returns once the actor has stopped. Close is called when the room is removed
+// from its Collection.
+func (r *Room) Close() {
+	r.cancel()
+	<-r.stopped
+}
 
-	return notify
+// Done returns a channel that is closed once the room has been closed.
+func (r *Room) Done() <-chan struct{} {
+	return r.ctx.Done()
 }
 
-func (r *Room) Notify() {
-	for l := range r.listeners {
-		select {
-		case l <- struct{}{}:
-		default:
-			// Client already has a pending notification; skip it.
-		}
-	}
+// Shutdown tells the room's listeners that the server is shutting down, so
+// that they can disconnect.
+func (r *Room) Shutdown() {
+	r.shutdownOnce.Do(func() {
+		close(r.shutdown)
+	})
+}
+
+// ShuttingDown returns a channel that is closed once Shutdown has been called.
+func (r *Room) ShuttingDown() <-chan struct{} {
+	return r.shutdown
 }
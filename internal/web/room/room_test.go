@@ -0,0 +1,280 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// renderPlayers renders the names of the players, and whether the viewer is
+// seated.
+func renderPlayers(g *game.Game, viewerId string) []byte {
+	v := g.ViewFor(viewerId)
+	var b []byte
+	for _, p := range v.Players {
+		b = append(b, p.Name...)
+	}
+	if v.Viewer >= 0 {
+		b = append(b, " (seated)"...)
+	}
+	return b
+}
+
+func TestRoom_Listen(t *testing.T) {
+	r := NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+
+	// Listeners come and go while the game changes
+	const clients = 50
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithCancel(context.Background())
+			updates := r.Listen(ctx, "", renderPlayers)
+			if i%2 == 0 {
+				cancel()
+				return
+			}
+			defer cancel()
+			for {
+				select {
+				case _, ok := <-updates:
+					if !ok {
+						return
+					}
+				case <-time.After(5 * time.Second):
+					t.Error("listener was not closed")
+					return
+				}
+			}
+		}()
+	}
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = r.Submit(context.Background(), string(rune('a'+i)), Join{Name: "Player"})
+		}()
+	}
+
+	// Let the listeners register before closing the room
+	time.Sleep(10 * time.Millisecond)
+	r.Close()
+	r.Close()
+	wg.Wait()
+
+	select {
+	case <-r.Done():
+	default:
+		t.Error("closed room is not done")
+	}
+	_, ok := <-r.Listen(context.Background(), "", renderPlayers)
+	if ok {
+		t.Error("listening to a closed room returned an open channel")
+	}
+}
+
+func TestRoom_publish(t *testing.T) {
+	r := NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	defer r.Close()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	renders := make(map[string]int)
+	render := func(g *game.Game, viewerId string) []byte {
+		mu.Lock()
+		renders[viewerId]++
+		mu.Unlock()
+		return renderPlayers(g, viewerId)
+	}
+	receive := func(updates <-chan Update) (Update, bool) {
+		select {
+		case u := <-updates:
+			return u, true
+		case <-time.After(50 * time.Millisecond):
+			return Update{}, false
+		}
+	}
+
+	_, err := r.Submit(ctx, "a", Join{Name: "A"})
+	if err != nil {
+		t.Fatalf("unexpected error joining: %v", err)
+	}
+	seated := r.Listen(ctx, "a", render)
+	spectators := []<-chan Update{
+		r.Listen(ctx, "x", render),
+		r.Listen(ctx, "y", render),
+		r.Listen(ctx, "", render),
+	}
+
+	// Every listener is sent the current state straight away
+	u, ok := receive(seated)
+	if !ok || string(u.State) != "A (seated)" || u.Version != 1 {
+		t.Errorf("got %q at version %d; want the seated view at version 1", u.State, u.Version)
+	}
+	for _, updates := range spectators {
+		u, ok := receive(updates)
+		if !ok || string(u.State) != "A" {
+			t.Errorf("got %q; want the spectator view", u.State)
+		}
+	}
+
+	// Changes are rendered once per distinct viewer
+	clear(renders)
+	_, err = r.Submit(ctx, "b", Join{Name: "B"})
+	if err != nil {
+		t.Fatalf("unexpected error joining: %v", err)
+	}
+	u, ok = receive(seated)
+	if !ok || string(u.State) != "AB (seated)" || u.Version != 2 {
+		t.Errorf("got %q at version %d; want the seated view at version 2", u.State, u.Version)
+	}
+	for _, updates := range spectators {
+		u, ok := receive(updates)
+		if !ok || string(u.State) != "AB" {
+			t.Errorf("got %q; want the spectator view", u.State)
+		}
+	}
+	mu.Lock()
+	if renders["a"] != 1 || renders[""] != 1 || len(renders) != 2 {
+		t.Errorf("got renders %v; want one for the player and one for spectators", renders)
+	}
+	mu.Unlock()
+
+	// Listeners are only sent the version if their state is unchanged
+	_, err = r.Submit(ctx, "c", Join{Name: ""})
+	if err != nil {
+		t.Fatalf("unexpected error joining: %v", err)
+	}
+	u, ok = receive(seated)
+	if !ok || u.State != nil || u.Version != 3 {
+		t.Errorf("got %q at version %d; want no state at version 3", u.State, u.Version)
+	}
+}
+
+func TestRoom_Submit(t *testing.T) {
+	r := NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	defer r.Close()
+	ctx := context.Background()
+
+	// Commands from many goroutines are applied one at a time
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.Submit(ctx, string(rune('a'+i)), Join{Name: "Player"})
+			if err != nil {
+				t.Errorf("unexpected error joining: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if r.Version() != 5 || len(r.Game.Players) != 5 {
+		t.Errorf("got version %d with %d players; want 5 with 5", r.Version(), len(r.Game.Players))
+	}
+
+	// Rejected commands do not change the version
+	version, err := r.Submit(ctx, "z", Join{Name: "Player"})
+	if !errors.Is(err, game.ErrGameFull) || version != 5 {
+		t.Errorf("got version %d, error %v; want 5, %v", version, err, game.ErrGameFull)
+	}
+	_, err = r.Submit(ctx, "z", Start{})
+	if !errors.Is(err, game.ErrPlayerNotFound) {
+		t.Errorf("got error %v starting as a spectator; want %v", err, game.ErrPlayerNotFound)
+	}
+
+	version, err = r.Submit(ctx, "a", Start{})
+	if err != nil || version != 6 {
+		t.Errorf("got version %d, error %v starting; want 6, nil", version, err)
+	}
+	_, err = r.Submit(ctx, "a", Leave{})
+	if !errors.Is(err, game.ErrGameStarted) {
+		t.Errorf("got error %v leaving a started game; want %v", err, game.ErrGameStarted)
+	}
+	_, err = r.Submit(ctx, "a", Play{Action: game.OrientAction{Flip: true}})
+	if err != nil {
+		t.Errorf("unexpected error orienting: %v", err)
+	}
+
+	// Players orienting at the same moment, from the same version, both
+	// succeed
+	for _, playerId := range []string{"b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.Submit(ctx, playerId, AtVersion{Version: 7, Command: Play{Action: game.OrientAction{}}})
+			if err != nil {
+				t.Errorf("unexpected error orienting as %s from version 7: %v", playerId, err)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, playerId := range []string{"d", "e"} {
+		_, err = r.Submit(ctx, playerId, AtVersion{Version: 7, Command: Play{Action: game.OrientAction{}}})
+		if err != nil {
+			t.Errorf("unexpected error orienting as %s from version 7: %v", playerId, err)
+		}
+	}
+
+	// Moves on the table made from an older version are rejected
+	current := r.Game.Players[r.Game.CurrentPlayer].Id
+	present := Play{Action: game.PresentAction{Start: 0, End: 1}}
+	_, err = r.Submit(ctx, current, AtVersion{Version: 10, Command: present})
+	if !errors.Is(err, game.ErrStaleVersion) {
+		t.Errorf("got error %v presenting from an old version; want %v", err, game.ErrStaleVersion)
+	}
+	version, err = r.Submit(ctx, current, AtVersion{Version: 11, Command: present})
+	if err != nil || version != 12 {
+		t.Errorf("got version %d, error %v presenting from the current version; want 12, nil", version, err)
+	}
+
+	r.Close()
+	_, err = r.Submit(ctx, "a", Play{Action: game.ReadyAction{}})
+	if !errors.Is(err, ErrRoomClosed) {
+		t.Errorf("got error %v submitting to a closed room; want %v", err, ErrRoomClosed)
+	}
+}
+
+// slowStore is a Store whose saves wait until they are released.
+type slowStore struct {
+	NopStore
+	saving  chan *game.Game
+	release chan struct{}
+}
+
+func (s *slowStore) Save(g *game.Game) error {
+	s.saving <- g
+	<-s.release
+	return nil
+}
+
+func TestRoom_Save(t *testing.T) {
+	store := &slowStore{saving: make(chan *game.Game), release: make(chan struct{})}
+	r := newRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}), store, 0)
+	defer r.Close()
+	defer close(store.release)
+	go func() {
+		_, _ = r.Submit(context.Background(), "a", Join{Name: "A"})
+	}()
+
+	// The game can be read while it is being saved
+	var saved *game.Game
+	select {
+	case saved = <-store.saving:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the game to be saved")
+	}
+	if !r.Mu.TryRLock() {
+		t.Fatal("expected the game to be unlocked while saving")
+	}
+	if saved == r.Game || saved.Version() != 1 || saved.Players[0].Name != "A" {
+		t.Errorf("expected a copy of the game at version 1, got %+v", saved)
+	}
+	r.Mu.RUnlock()
+}
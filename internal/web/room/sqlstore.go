@@ -0,0 +1,334 @@
+package room
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+	_ "modernc.org/sqlite"
+)
+
+const sqlSchema = `
+CREATE TABLE IF NOT EXISTS games (
+	id         TEXT PRIMARY KEY,
+	mode       TEXT NOT NULL,
+	phase      TEXT NOT NULL,
+	round      INTEGER NOT NULL,
+	state      BLOB NOT NULL,
+	created_at TEXT NOT NULL,
+	updated_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS seats (
+	game_id   TEXT NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	seat      INTEGER NOT NULL,
+	player_id TEXT NOT NULL,
+	name      TEXT NOT NULL,
+	PRIMARY KEY (game_id, seat)
+);
+CREATE INDEX IF NOT EXISTS seats_player_id ON seats (player_id);
+CREATE TABLE IF NOT EXISTS events (
+	game_id   TEXT NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+	seq       INTEGER NOT NULL,
+	time      TEXT NOT NULL,
+	kind      TEXT NOT NULL,
+	player_id TEXT,
+	data      BLOB NOT NULL,
+	PRIMARY KEY (game_id, seq)
+);
+-- Results outlive the games they came from, so that history is kept after a
+-- finished game's room is removed.
+CREATE TABLE IF NOT EXISTS results (
+	game_id     TEXT NOT NULL,
+	mode        TEXT NOT NULL,
+	finished_at TEXT NOT NULL,
+	seat        INTEGER NOT NULL,
+	player_id   TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	points      INTEGER NOT NULL,
+	rank        INTEGER NOT NULL,
+	won         INTEGER NOT NULL,
+	PRIMARY KEY (game_id, seat)
+);
+CREATE INDEX IF NOT EXISTS results_player_id ON results (player_id);
+CREATE INDEX IF NOT EXISTS results_finished_at ON results (finished_at);
+`
+
+// sqlTimeFormat sorts lexically in time order, so that times stored as text
+// can be compared in queries.
+const sqlTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// ErrGameNotFound is returned when a game is not in the store.
+var ErrGameNotFound = errors.New("game not found")
+
+// Result is one player's outcome in a finished game.
+type Result struct {
+	GameId     string
+	Mode       string
+	FinishedAt time.Time
+	Seat       int
+	PlayerId   string
+	Name       string
+	Points     int
+	// Rank is 1 for the winners. Tied players share a rank.
+	Rank int
+	Won  bool
+}
+
+// SQLStore is a Store backed by an embedded SQLite database. Alongside each
+// game's state it records the players' seats, every Event, and the results of
+// finished games, so that they can be queried.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore opens the SQLite database at path, creating it if necessary.
+func NewSQLStore(path string) (*SQLStore, error) {
+	dsn := url.URL{
+		Scheme:   "file",
+		Opaque:   url.PathEscape(path),
+		RawQuery: "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)",
+	}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer. Serializing access through one
+	// connection avoids "database is locked" errors.
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(sqlSchema)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLStore) Save(g *game.Game) (err error) {
+	state, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(sqlTimeFormat)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`
+		INSERT INTO games (id, mode, phase, round, state, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			phase = excluded.phase,
+			round = excluded.round,
+			state = excluded.state,
+			updated_at = excluded.updated_at`,
+		g.Id, g.Mode.String(), g.Phase.String(), g.Round, state, now, now)
+	if err != nil {
+		return err
+	}
+
+	// Players may only come and go in the lobby, so the seats are simply
+	// rewritten.
+	_, err = tx.Exec(`DELETE FROM seats WHERE game_id = ?`, g.Id)
+	if err != nil {
+		return err
+	}
+	for i := range g.Players {
+		p := &g.Players[i]
+		_, err = tx.Exec(`INSERT INTO seats (game_id, seat, player_id, name) VALUES (?, ?, ?, ?)`,
+			g.Id, i, p.Id, p.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Events are only ever appended, so only those not yet stored are written.
+	var stored int
+	err = tx.QueryRow(`SELECT COALESCE(MAX(seq), 0) FROM events WHERE game_id = ?`, g.Id).Scan(&stored)
+	if err != nil {
+		return err
+	}
+	for _, e := range g.Events[min(stored, len(g.Events)):] {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO events (game_id, seq, time, kind, player_id, data) VALUES (?, ?, ?, ?, ?, ?)`,
+			g.Id, e.Seq, e.Time.UTC().Format(sqlTimeFormat), string(e.Kind), sql.NullString{String: e.PlayerId, Valid: e.PlayerId != ""}, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.IsGameOver() {
+		err = saveResults(tx, g)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func saveResults(tx *sql.Tx, g *game.Game) error {
+	finishedAt := time.Now().UTC()
+	if len(g.Events) > 0 {
+		finishedAt = g.Events[len(g.Events)-1].Time
+	}
+	winningPoints := g.Winners()[0].Points
+
+	rank := 0
+	prevPoints := 0
+	for i, p := range g.Standings() {
+		if i == 0 || p.Points != prevPoints {
+			rank = i + 1
+			prevPoints = p.Points
+		}
+		seat, err := g.GetPlayerIndex(p.Id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO results (game_id, mode, finished_at, seat, player_id, name, points, rank, won)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (game_id, seat) DO NOTHING`,
+			g.Id, g.Mode.String(), finishedAt.UTC().Format(sqlTimeFormat), seat, p.Id, p.Name, p.Points, rank, p.Points == winningPoints)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes a game along with its seats and events. The results of a
+// finished game are kept.
+func (s *SQLStore) Delete(gameId string) error {
+	_, err := s.db.Exec(`DELETE FROM games WHERE id = ?`, gameId)
+	return err
+}
+
+func (s *SQLStore) LoadAll() ([]*game.Game, error) {
+	rows, err := s.db.Query(`SELECT id, state FROM games ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []*game.Game
+	for rows.Next() {
+		var id string
+		var state []byte
+		err = rows.Scan(&id, &state)
+		if err != nil {
+			return nil, err
+		}
+		g := &game.Game{}
+		err = json.Unmarshal(state, g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load game %s: %w", id, err)
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
+// Log retrieves the stored event Log of a game.
+func (s *SQLStore) Log(gameId string) (game.Log, error) {
+	var log game.Log
+	var state []byte
+	err := s.db.QueryRow(`SELECT state FROM games WHERE id = ?`, gameId).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return log, ErrGameNotFound
+	}
+	if err != nil {
+		return log, err
+	}
+	g := &game.Game{}
+	err = json.Unmarshal(state, g)
+	if err != nil {
+		return log, err
+	}
+	log = game.Log{Id: g.Id, Mode: g.Mode, Seed: g.Seed}
+
+	rows, err := s.db.Query(`SELECT data FROM events WHERE game_id = ? ORDER BY seq`, gameId)
+	if err != nil {
+		return log, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return log, err
+		}
+		var e game.Event
+		err = json.Unmarshal(data, &e)
+		if err != nil {
+			return log, err
+		}
+		log.Events = append(log.Events, e)
+	}
+	return log, rows.Err()
+}
+
+// ResultsSince lists the results of every game finished at or after since,
+// most recent first.
+func (s *SQLStore) ResultsSince(since time.Time) ([]Result, error) {
+	return s.queryResults(`
+		SELECT game_id, mode, finished_at, seat, player_id, name, points, rank, won
+		FROM results
+		WHERE finished_at >= ?
+		ORDER BY finished_at DESC, game_id, rank, seat`,
+		since.UTC().Format(sqlTimeFormat))
+}
+
+// PlayerHistory lists the results of every finished game in which the player
+// had a seat, most recent first. Every player's result is included for each
+// game, not only the given player's.
+func (s *SQLStore) PlayerHistory(playerId string) ([]Result, error) {
+	return s.queryResults(`
+		SELECT game_id, mode, finished_at, seat, player_id, name, points, rank, won
+		FROM results
+		WHERE game_id IN (SELECT game_id FROM results WHERE player_id = ?)
+		ORDER BY finished_at DESC, game_id, rank, seat`,
+		playerId)
+}
+
+func (s *SQLStore) queryResults(query string, args ...any) ([]Result, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		var r Result
+		var finishedAt string
+		err = rows.Scan(&r.GameId, &r.Mode, &finishedAt, &r.Seat, &r.PlayerId, &r.Name, &r.Points, &r.Rank, &r.Won)
+		if err != nil {
+			return nil, err
+		}
+		r.FinishedAt, err = time.Parse(sqlTimeFormat, finishedAt)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
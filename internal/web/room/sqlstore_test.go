@@ -0,0 +1,125 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+func TestSQLStore(t *testing.T) {
+	store, err := NewSQLStore(filepath.Join(t.TempDir(), "prospect.db"))
+	if err != nil {
+		t.Fatalf("unexpected error opening store: %v", err)
+	}
+	defer store.Close()
+
+	g := game.New("game", game.ModeStandard, [2]uint64{1, 2})
+	for i := range 3 {
+		err = g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("Player %d", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	err = g.Start()
+	if err != nil {
+		t.Fatalf("unexpected error starting game: %v", err)
+	}
+
+	// Save after every move, as the server does
+	err = g.PlayRandomly(rand.New(rand.NewPCG(3, 4)), func(string, game.Action) bool {
+		err := store.Save(g)
+		if err != nil {
+			t.Fatalf("unexpected error saving game: %v", err)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error playing random moves: %v", err)
+	}
+
+	games, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("unexpected error loading games: %v", err)
+	}
+	if len(games) != 1 || !reflect.DeepEqual(games[0].Players, g.Players) {
+		t.Errorf("loaded games do not match saved game")
+	}
+
+	log, err := store.Log(g.Id)
+	if err != nil {
+		t.Fatalf("unexpected error loading log: %v", err)
+	}
+	if !reflect.DeepEqual(log, g.Log()) {
+		t.Errorf("stored log does not match game log")
+	}
+
+	results, err := store.ResultsSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error querying results: %v", err)
+	}
+	if len(results) != len(g.Players) {
+		t.Fatalf("expected %d results, got %v", len(g.Players), len(results))
+	}
+	winners := g.Winners()
+	if results[0].Rank != 1 || !results[0].Won || results[0].Points != winners[0].Points {
+		t.Errorf("expected first result to be a winner with %d points, got %+v", winners[0].Points, results[0])
+	}
+	results, err = store.ResultsSince(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error querying results: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results from the future, got %v", len(results))
+	}
+
+	history, err := store.PlayerHistory("1")
+	if err != nil {
+		t.Fatalf("unexpected error querying history: %v", err)
+	}
+	if len(history) != len(g.Players) {
+		t.Errorf("expected %d history results, got %v", len(g.Players), len(history))
+	}
+
+	// Deleting the game keeps its results
+	err = store.Delete(g.Id)
+	if err != nil {
+		t.Fatalf("unexpected error deleting game: %v", err)
+	}
+	games, err = store.LoadAll()
+	if err != nil || len(games) != 0 {
+		t.Errorf("expected no games after delete, got %v, %v", len(games), err)
+	}
+	_, err = store.Log(g.Id)
+	if !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("expected %v loading deleted log, got %v", ErrGameNotFound, err)
+	}
+	history, err = store.PlayerHistory("1")
+	if err != nil || len(history) != len(g.Players) {
+		t.Errorf("expected %d history results after delete, got %v, %v", len(g.Players), len(history), err)
+	}
+}
+
+func TestNewSQLStore_path(t *testing.T) {
+	// Characters that mean something in a URI are part of the file name
+	path := filepath.Join(t.TempDir(), "prospect?mode=ro#100%.db")
+	store, err := NewSQLStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error opening store: %v", err)
+	}
+	defer store.Close()
+	err = store.Save(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	if err != nil {
+		t.Fatalf("unexpected error saving game: %v", err)
+	}
+	_, err = os.Stat(path)
+	if err != nil {
+		t.Errorf("expected the database at %q, got %v", path, err)
+	}
+}
@@ -0,0 +1,123 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// Store persists games so that rooms survive a server restart.
+type Store interface {
+	// Save stores the current state of a game, replacing any earlier state.
+	Save(g *game.Game) error
+	// Delete removes a game from the store. Deleting a game that is not in
+	// the store is not an error.
+	Delete(gameId string) error
+	// LoadAll retrieves every stored game.
+	LoadAll() ([]*game.Game, error)
+}
+
+// NopStore is a Store that keeps nothing, for servers without persistence.
+type NopStore struct{}
+
+func (NopStore) Save(*game.Game) error          { return nil }
+func (NopStore) Delete(string) error            { return nil }
+func (NopStore) LoadAll() ([]*game.Game, error) { return nil, nil }
+
+const fileStoreExt = ".json"
+
+// FileStore is a Store that keeps one JSON file per game in a directory.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(gameId string) (string, error) {
+	if gameId == "" || filepath.Base(gameId) != gameId || strings.HasPrefix(gameId, ".") {
+		return "", fmt.Errorf("invalid game id: %q", gameId)
+	}
+	return filepath.Join(s.dir, gameId+fileStoreExt), nil
+}
+
+func (s *FileStore) Save(g *game.Game) error {
+	path, err := s.path(g.Id)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it into place, so that a crash
+	// part way through cannot leave a truncated game behind.
+	f, err := os.CreateTemp(s.dir, "."+g.Id+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) Delete(gameId string) error {
+	path, err := s.path(gameId)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *FileStore) LoadAll() ([]*game.Game, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var games []*game.Game
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != fileStoreExt {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		g := &game.Game{}
+		err = json.Unmarshal(data, g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
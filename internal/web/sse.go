@@ -0,0 +1,147 @@
+package web
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+)
+
+// Names of the SSE events sent to clients watching a game. Elements choose
+// which to handle with data-sse-swap.
+const (
+	// sseEventState carries the whole rendered game.
+	sseEventState = "state"
+	// sseEventVersion is sent in place of sseEventState when the game has
+	// changed but looks the same to the client, so that the client still
+	// learns the Version from the event's id.
+	sseEventVersion = "version"
+	// sseEventToast carries a short message about something that happened.
+	sseEventToast = "toast"
+	// sseEventRoundEnd is sent when a round finishes.
+	sseEventRoundEnd = "round-end"
+)
+
+// Clients wait about this long before reconnecting after a stream ends.
+const (
+	sseDefaultRetry    = 2 * time.Second
+	sseOverloadedRetry = 30 * time.Second
+)
+
+// sseRetry adds up to 50% jitter to a retry delay, so that clients turned
+// away together do not all return together.
+func sseRetry(d time.Duration) time.Duration {
+	return d + rand.N(d/2)
+}
+
+type sseEvent struct {
+	// Retry, if set, tells the client how long to wait before reconnecting.
+	Retry time.Duration
+	// Id is the Version of the game after the event. It is only sent on the
+	// last event of a batch, so that a client which reconnects part way
+	// through a batch is sent it again.
+	Id    string
+	Event string
+	Data  string
+}
+
+func writeSseEvent(w io.Writer, e sseEvent) error {
+	var b strings.Builder
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry.Milliseconds())
+	}
+	if e.Id != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.Id)
+	}
+	if e.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", e.Event)
+	}
+	if e.Data != "" {
+		for _, line := range strings.Split(e.Data, "\n") {
+			fmt.Fprintf(&b, "data: %s\n", line)
+		}
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// lastEventVersion returns the game Version a reconnecting client last saw,
+// or -1 if it is not reconnecting.
+func lastEventVersion(lastEventId string) int {
+	version, err := strconv.Atoi(lastEventId)
+	if err != nil || version < 0 {
+		return -1
+	}
+	return version
+}
+
+// describeEvents lists toasts and round-end events for what happened in the
+// game between the given versions, as seen by the given player. Players are
+// not told about their own actions, nor about routine ones such as choosing a
+// hand orientation.
+func describeEvents(g *game.Game, playerId string, since, until int) []sseEvent {
+	until = min(until, len(g.Events))
+	if since < 0 || since >= until {
+		return nil
+	}
+	var events []sseEvent
+	for _, e := range g.Events[since:until] {
+		if e.PlayerId != "" && e.PlayerId == playerId {
+			continue
+		}
+		name := html.EscapeString(playerName(g, e.PlayerId))
+		var message string
+		switch e.Kind {
+		case game.EventJoin:
+			message = name + " joined the game"
+		case game.EventLeave:
+			message = name + " left the game"
+		case game.EventStart:
+			message = "The game has started"
+		case game.EventTraining:
+			message = name + " turned off training hints"
+			if e.Training {
+				message = name + " turned on training hints"
+			}
+		case game.EventPresent:
+			message = fmt.Sprintf("%s presented %d cards", name, e.End-e.Start)
+			if e.End-e.Start == 1 {
+				message = name + " presented 1 card"
+			}
+		case game.EventProspect:
+			message = name + " prospected"
+		case game.EventPass:
+			message = name + " passed"
+		case game.EventRoundEnd:
+			events = append(events, sseEvent{
+				Event: sseEventRoundEnd,
+				Data:  fmt.Sprintf("Round %d is over", e.Round),
+			})
+			continue
+		default:
+			continue
+		}
+		events = append(events, sseEvent{Event: sseEventToast, Data: message})
+	}
+	return events
+}
+
+// playerName finds the name a player joined the game with, even if they have
+// since left.
+func playerName(g *game.Game, playerId string) string {
+	if p := g.GetPlayerById(playerId); p != nil {
+		return p.Name
+	}
+	for _, e := range g.Events {
+		if e.Kind == game.EventJoin && e.PlayerId == playerId {
+			return e.Name
+		}
+	}
+	return "Someone"
+}
@@ -0,0 +1,209 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/djcrock/prospect/internal/game"
+	"github.com/djcrock/prospect/internal/web/room"
+)
+
+func TestWriteSseEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event sseEvent
+		want  string
+	}{
+		{"empty", sseEvent{}, "\n"},
+		{"data", sseEvent{Data: "hello"}, "data: hello\n\n"},
+		{
+			"multi-line data",
+			sseEvent{Event: sseEventState, Data: "<p>\nhi\n</p>"},
+			"event: state\ndata: <p>\ndata: hi\ndata: </p>\n\n",
+		},
+		{
+			"every field",
+			sseEvent{Retry: 2500 * time.Millisecond, Id: "7", Event: sseEventToast, Data: "hi"},
+			"retry: 2500\nid: 7\nevent: toast\ndata: hi\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := writeSseEvent(&b, tt.event)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q; want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLastEventVersion(t *testing.T) {
+	tests := []struct {
+		lastEventId string
+		want        int
+	}{
+		{"", -1},
+		{"0", 0},
+		{"42", 42},
+		{"-3", -1},
+		{"abc", -1},
+	}
+	for _, tt := range tests {
+		got := lastEventVersion(tt.lastEventId)
+		if got != tt.want {
+			t.Errorf("lastEventVersion(%q) = %d; want %d", tt.lastEventId, got, tt.want)
+		}
+	}
+}
+
+func TestSseRetry(t *testing.T) {
+	for range 100 {
+		got := sseRetry(sseDefaultRetry)
+		if got < sseDefaultRetry || got >= sseDefaultRetry*3/2 {
+			t.Fatalf("got retry %v; want between %v and %v", got, sseDefaultRetry, sseDefaultRetry*3/2)
+		}
+	}
+}
+
+func TestDescribeEvents(t *testing.T) {
+	g := game.New("game", game.ModeStandard, [2]uint64{1, 2})
+	for i := range 3 {
+		err := g.AddPlayer(fmt.Sprintf("%d", i), fmt.Sprintf("<P%d>", i))
+		if err != nil {
+			t.Fatalf("unexpected error adding player %d: %v", i, err)
+		}
+	}
+	_ = g.Start()
+	for i := range g.Players {
+		_ = g.DecideHandOrientation(g.Players[i].Id, false)
+	}
+	current := g.Players[g.CurrentPlayer].Id
+	_ = g.Present(current, 0, 1)
+	// Events: 3 joins, start, 3 orients, present
+
+	tests := []struct {
+		name     string
+		playerId string
+		since    int
+		until    int
+		want     []sseEvent
+	}{
+		{"not reconnecting", "x", -1, 8, nil},
+		{"up to date", "x", 8, 8, nil},
+		{
+			"joins, escaped",
+			"x", 0, 2,
+			[]sseEvent{
+				{Event: sseEventToast, Data: "&lt;P0&gt; joined the game"},
+				{Event: sseEventToast, Data: "&lt;P1&gt; joined the game"},
+			},
+		},
+		{"own events hidden", "0", 0, 1, nil},
+		{
+			"orients are routine",
+			"x", 3, 7,
+			[]sseEvent{{Event: sseEventToast, Data: "The game has started"}},
+		},
+		{
+			"until capped at the end of the log",
+			"x", 7, 100,
+			[]sseEvent{{Event: sseEventToast, Data: fmt.Sprintf("&lt;P%s&gt; presented 1 card", current)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describeEvents(g, tt.playerId, tt.since, tt.until)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("round end", func(t *testing.T) {
+		g := game.New("game", game.ModeStandard, [2]uint64{1, 2})
+		for i := range 3 {
+			_ = g.AddPlayer(fmt.Sprintf("%d", i), "P")
+		}
+		_ = g.Start()
+		for i := range g.Players {
+			_ = g.DecideHandOrientation(g.Players[i].Id, false)
+		}
+		since := g.Version()
+		for g.Round == 1 && !g.IsRoundOver() {
+			playerId := g.Players[g.CurrentPlayer].Id
+			_ = g.Apply(playerId, g.LegalActions(playerId)[0])
+		}
+		events := describeEvents(g, "x", since, g.Version())
+		last := events[len(events)-1]
+		want := sseEvent{Event: sseEventRoundEnd, Data: "Round 1 is over"}
+		if last != want {
+			t.Errorf("got last event %v; want %v", last, want)
+		}
+	})
+}
+
+func TestRenderGameSse(t *testing.T) {
+	s := &server{logger: log.New(io.Discard, "", 0)}
+	r := room.NewRoom(game.New("game", game.ModeStandard, [2]uint64{1, 2}))
+	defer r.Close()
+	ctx := context.Background()
+
+	submit := func(playerId string, c room.Command) {
+		t.Helper()
+		_, err := r.Submit(ctx, playerId, c)
+		if err != nil {
+			t.Fatalf("unexpected error submitting %T as %s: %v", c, playerId, err)
+		}
+	}
+	receive := func(updates <-chan room.Update) room.Update {
+		t.Helper()
+		select {
+		case u := <-updates:
+			return u
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an update")
+			return room.Update{}
+		}
+	}
+
+	for _, playerId := range []string{"a", "b", "c"} {
+		submit(playerId, room.Join{Name: "Player " + playerId})
+	}
+	submit("a", room.Start{})
+	for _, playerId := range []string{"a", "b", "c"} {
+		submit(playerId, room.Play{Action: game.OrientAction{}})
+	}
+
+	updates := r.Listen(ctx, "a", s.renderGameSse)
+	u := receive(updates)
+	if u.State == nil || u.Version != r.Version() {
+		t.Fatalf("got %d bytes at version %d; want the game at version %d", len(u.State), u.Version, r.Version())
+	}
+
+	// Turning on training mode mid-round changes nothing the player can see,
+	// so only the new version is sent
+	submit("a", room.SetTraining{Enabled: true})
+	u = receive(updates)
+	if u.State != nil || u.Version != r.Version() {
+		t.Errorf("got %d bytes at version %d; want only version %d", len(u.State), u.Version, r.Version())
+	}
+
+	r.Mu.RLock()
+	current := r.Game.Players[r.Game.CurrentPlayer].Id
+	action := r.Game.LegalActions(current)[0]
+	r.Mu.RUnlock()
+	submit(current, room.Play{Action: action})
+	u = receive(updates)
+	if u.State == nil || u.Version != r.Version() {
+		t.Errorf("got %d bytes at version %d; want the game at version %d", len(u.State), u.Version, r.Version())
+	}
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,8 +11,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/djcrock/prospect/internal/bot"
 	"github.com/djcrock/prospect/internal/game"
 	"github.com/djcrock/prospect/internal/web/room"
 	"github.com/djcrock/prospect/internal/web/static"
@@ -22,15 +25,25 @@ type server struct {
 	mu    sync.RWMutex
 	rooms *room.Collection
 
+	// sseClients counts the open SSE streams, which may not exceed
+	// maxSseClients.
+	sseClients    atomic.Int64
+	maxSseClients int64
+
 	logger *log.Logger
 }
 
+// NewApp creates the web server. At most maxSseClients clients may stream
+// updates at once; beyond that, clients are asked to reconnect later.
 func NewApp(
 	logger *log.Logger,
+	rooms *room.Collection,
+	maxSseClients int,
 ) http.Handler {
 	s := &server{
-		rooms:  room.NewCollection(),
-		logger: logger,
+		rooms:         rooms,
+		maxSseClients: int64(maxSseClients),
+		logger:        logger,
 	}
 	mux := http.NewServeMux()
 
@@ -40,10 +53,16 @@ func NewApp(
 	mux.Handle("GET /game/{id}", s.withGameRoom(http.HandlerFunc(s.handleGetGame)))
 	mux.Handle("GET /game/{id}/sse", s.withGameRoom(http.HandlerFunc(s.handleGetGameSse)))
 	mux.Handle("POST /game/{id}/players", s.withGameRoom(http.HandlerFunc(s.handlePostGamePlayers)))
+	mux.Handle("POST /game/{id}/bots", s.withGameRoom(http.HandlerFunc(s.handlePostGameBots)))
+	mux.Handle("POST /game/{id}/bots/remove", s.withGameRoom(http.HandlerFunc(s.handlePostGameBotsRemove)))
 	mux.Handle("POST /game/{id}/leave", s.withGameRoom(http.HandlerFunc(s.handlePostGameLeave)))
+	mux.Handle("POST /game/{id}/training/{setting}", s.withGameRoom(http.HandlerFunc(s.handlePostGameTraining)))
 	mux.Handle("POST /game/{id}/start", s.withGameRoom(http.HandlerFunc(s.handlePostGameStart)))
 	mux.Handle("POST /game/{id}/decide/{direction}", s.withGameRoom(http.HandlerFunc(s.handlePostGameDecide)))
 	mux.Handle("POST /game/{id}/present/{presentation}", s.withGameRoom(http.HandlerFunc(s.handlePostGamePresent)))
+	mux.Handle("POST /game/{id}/prospect", s.withGameRoom(http.HandlerFunc(s.handlePostGameProspect)))
+	mux.Handle("POST /game/{id}/pass", s.withGameRoom(http.HandlerFunc(s.handlePostGamePass)))
+	mux.Handle("POST /game/{id}/ready", s.withGameRoom(http.HandlerFunc(s.handlePostGameReady)))
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Vary", "HX-Request")
@@ -59,23 +78,129 @@ type baseData struct {
 type gameData struct {
 	Base                  baseData
 	IsSse                 bool
-	Game                  *game.Game
-	Player                *game.Player
+	Game                  *game.View
+	Player                *game.PlayerView
 	PlayablePresentations [][]game.Card
 	YourTurn              bool
 	CanPresent            bool
+	CanProspect           bool
+	IsDecidingPresent     bool
+	ProspectOptions       []prospectOption
+	PlayerStatuses        []playerStatus
+	RoundSummaries        []roundSummary
+	Flash                 string
+}
+
+// roundSummary describes how a player fared in the round that just ended.
+type roundSummary struct {
+	Name    string
+	IsYou   bool
+	IsReady bool
+	Points  int
+	Result  game.RoundResult
+}
+
+func getRoundSummaries(v *game.View) []roundSummary {
+	summaries := make([]roundSummary, 0, len(v.Players))
+	for i := range v.Players {
+		p := &v.Players[i]
+		if len(p.RoundResults) == 0 {
+			continue
+		}
+		summaries = append(summaries, roundSummary{
+			Name:    p.Name,
+			IsYou:   p.IsViewer,
+			IsReady: p.IsReady,
+			Points:  p.Points,
+			Result:  p.RoundResults[len(p.RoundResults)-1],
+		})
+	}
+	return summaries
+}
+
+// playerStatus is the publicly visible state of a seated player.
+type playerStatus struct {
+	Name                      string
+	IsYou                     bool
+	IsCurrentPlayer           bool
+	IsLastPlayerToPresent     bool
+	HasDecidedHandOrientation bool
+	HandSize                  int
+	Points                    int
+	ScorePile                 int
+	ProspectTokens            int
+	ProspectAndPresentChips   int
+}
+
+func getPlayerStatuses(v *game.View) []playerStatus {
+	statuses := make([]playerStatus, len(v.Players))
+	for i := range v.Players {
+		p := &v.Players[i]
+		statuses[i] = playerStatus{
+			Name:                      p.Name,
+			IsYou:                     p.IsViewer,
+			IsCurrentPlayer:           i == v.CurrentPlayer,
+			IsLastPlayerToPresent:     i == v.LastPlayerToPresent && len(v.Presentation) > 0,
+			HasDecidedHandOrientation: p.HasDecidedHandOrientation,
+			HandSize:                  p.HandSize,
+			Points:                    p.Points,
+			ScorePile:                 p.ScorePile,
+			ProspectTokens:            p.ProspectTokens,
+			ProspectAndPresentChips:   p.ProspectAndPresentChips,
+		}
+	}
+	return statuses
+}
+
+// prospectOption is a card that may be taken from one end of the presentation,
+// in one of its two orientations.
+type prospectOption struct {
+	Value string
+	Card  game.Card
+}
+
+func getProspectOptions(presentation []game.Card) []prospectOption {
+	if len(presentation) == 0 {
+		return nil
+	}
+	left := presentation[0]
+	options := []prospectOption{
+		{Value: "left-up", Card: left},
+		{Value: "left-down", Card: left.Flip()},
+	}
+	if len(presentation) > 1 {
+		right := presentation[len(presentation)-1]
+		options = append(options,
+			prospectOption{Value: "right-up", Card: right},
+			prospectOption{Value: "right-down", Card: right.Flip()},
+		)
+	}
+	return options
 }
 
+// prepareGameData renders the Game as seen by the given player. Templates
+// only ever receive a game.View, so hidden information cannot leak into them.
 func prepareGameData(g *game.Game, playerId string) *gameData {
-	playerIndex, _ := g.GetPlayerIndex(playerId)
+	v := g.ViewFor(playerId)
 	data := &gameData{
 		Base:                  baseData{Title: "Game"},
-		Game:                  g,
-		Player:                g.GetPlayerById(playerId),
-		PlayablePresentations: g.PlayablePresentations(playerId),
-		YourTurn:              playerIndex == g.CurrentPlayer,
+		Game:                  v,
+		Player:                v.Player(),
+		PlayablePresentations: v.PlayablePresentations(),
+		YourTurn:              v.IsYourTurn(),
+		PlayerStatuses:        getPlayerStatuses(v),
+	}
+	// After prospecting, a player holding a prospect-and-present chip may
+	// present immediately or pass; they may not prospect again.
+	data.IsDecidingPresent = v.IsDecidingPresent()
+	if v.IsRoundOver() {
+		data.RoundSummaries = getRoundSummaries(v)
+	}
+	data.CanPresent = data.YourTurn && len(data.PlayablePresentations) > 0
+	data.CanProspect = data.YourTurn && v.Phase == game.PhasePlaying && len(v.Presentation) > 0
+	if data.CanProspect {
+		data.ProspectOptions = getProspectOptions(v.Presentation)
 	}
-	data.CanPresent = data.YourTurn && len(data.PlayablePresentations) > 0 && g.HavePlayersDecidedHandOrientation()
 	return data
 }
 
@@ -84,7 +209,10 @@ func (s *server) retrieveGameRoom(gameId string) *room.Room {
 }
 
 func (s *server) removeGame(gameId string) {
-	s.rooms.RemoveRoom(gameId)
+	err := s.rooms.RemoveRoom(gameId)
+	if err != nil {
+		s.logger.Printf("failed to delete game %s: %v", gameId, err)
+	}
 }
 
 func (s *server) redirectToGame(w http.ResponseWriter, r *http.Request, g *game.Game) {
@@ -103,7 +231,43 @@ func (s *server) redirectToGame(w http.ResponseWriter, r *http.Request, g *game.
 }
 
 func (s *server) renderGame(w io.Writer, r *http.Request, g *game.Game) {
+	s.executeGameTemplate(w, r, prepareGameData(g, getPlayerId(r)))
+}
+
+// errMalformedRequest is returned for requests that could not be parsed into
+// a game action.
+var errMalformedRequest = errors.New("that request was not understood")
+
+// gameErrorStatus chooses the HTTP status code to use when an action is
+// rejected with the given error.
+func gameErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errMalformedRequest),
+		errors.Is(err, bot.ErrUnknownStrategy):
+		return http.StatusBadRequest
+	case errors.Is(err, game.ErrPlayerNotFound):
+		return http.StatusForbidden
+	case errors.Is(err, game.ErrOutOfRange),
+		errors.Is(err, game.ErrInvalidPresentation),
+		errors.Is(err, game.ErrPresentationTooWeak),
+		errors.Is(err, game.ErrNothingToProspect):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusConflict
+	}
+}
+
+// renderGameError re-renders the game for a player whose action was rejected,
+// with a flash message explaining why. HTMX is configured in base.tmpl to swap
+// in these error responses.
+func (s *server) renderGameError(w http.ResponseWriter, r *http.Request, g *game.Game, err error) {
 	data := prepareGameData(g, getPlayerId(r))
+	data.Flash = err.Error()
+	w.WriteHeader(gameErrorStatus(err))
+	s.executeGameTemplate(w, r, data)
+}
+
+func (s *server) executeGameTemplate(w io.Writer, r *http.Request, data *gameData) {
 	var err error
 	if r.Header.Get("HX-Request") == "true" {
 		err = templates.Game.ExecutePartial(w, data)
@@ -115,13 +279,16 @@ func (s *server) renderGame(w io.Writer, r *http.Request, g *game.Game) {
 	}
 }
 
-func (s *server) renderGameSse(w io.Writer, r *http.Request, g *game.Game) {
-	data := prepareGameData(g, getPlayerId(r))
+// renderGameSse is the room.Renderer for SSE updates.
+func (s *server) renderGameSse(g *game.Game, viewerId string) []byte {
+	data := prepareGameData(g, viewerId)
 	data.IsSse = true
-	err := templates.Game.ExecutePartial(w, data)
+	buf := &bytes.Buffer{}
+	err := templates.Game.ExecutePartial(buf, data)
 	if err != nil {
 		s.logger.Printf("failed to execute template: %v", err)
 	}
+	return buf.Bytes()
 }
 
 func (s *server) handleIndex(w http.ResponseWriter, _ *http.Request) {
@@ -132,106 +299,143 @@ func (s *server) handleIndex(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (s *server) handlePostGame(w http.ResponseWriter, r *http.Request) {
-	gameRoom := s.rooms.NewRoom()
+	mode := game.ModeStandard
+	if r.FormValue("mode") == "two-player" {
+		mode = game.ModeTwoPlayer
+	}
+	gameRoom := s.rooms.NewRoom(mode)
 	playerId := gameRoom.EnsurePlayer("")
-	err := gameRoom.Game.AddPlayer(playerId, r.FormValue("name"))
+
+	_, err := gameRoom.Submit(r.Context(), playerId, room.Join{Name: r.FormValue("name")})
 	if err != nil {
+		s.removeGame(gameRoom.Game.Id)
 		http.Error(w, fmt.Sprintf("failed to create game: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	r = withPlayerIdContext(r, gameRoom.Game.Players[0].Id)
-	setPlayerIdCookie(w, r, gameRoom.Game.Id, gameRoom.Game.Players[0].Id)
+	r = withPlayerIdContext(r, playerId)
+	setPlayerIdCookie(w, r, gameRoom.Game.Id, playerId)
+
+	gameRoom.Mu.RLock()
+	defer gameRoom.Mu.RUnlock()
 	s.redirectToGame(w, r, gameRoom.Game)
 }
 
-func (s *server) handlePostGamePlayers(w http.ResponseWriter, r *http.Request) {
-	gr := getGameRoom(r)
-	playerId := getPlayerId(r)
+// requestVersion returns the Version of the game the request was made from.
+// Rendered actions include it, but other clients may leave it out.
+func requestVersion(r *http.Request) (version int, ok bool, err error) {
+	value := r.FormValue("version")
+	if value == "" {
+		return 0, false, nil
+	}
+	version, err = strconv.Atoi(value)
+	if err != nil {
+		return 0, false, errMalformedRequest
+	}
+	return version, true, nil
+}
 
-	gr.Mu.Lock()
-	defer gr.Mu.Unlock()
-	p := gr.Game.GetPlayerById(playerId)
-	if p != nil {
-		s.renderGame(w, r, gr.Game)
+// submit applies a command to a room's game on behalf of the requesting
+// player, then renders the game for them. If the request was made from an
+// older version of the game, the command is rejected.
+func (s *server) submit(w http.ResponseWriter, r *http.Request, gr *room.Room, cmd room.Command) {
+	version, ok, err := requestVersion(r)
+	if err != nil {
+		s.logger.Printf("invalid version: %s", r.FormValue("version"))
+		s.reject(w, r, gr, err)
 		return
 	}
+	if ok {
+		cmd = room.AtVersion{Version: version, Command: cmd}
+	}
 
-	playerName := r.FormValue("name")
+	_, err = gr.Submit(r.Context(), getPlayerId(r), cmd)
 
-	err := gr.Game.AddPlayer(playerId, playerName)
+	gr.Mu.RLock()
+	defer gr.Mu.RUnlock()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to add player: %v", err), http.StatusBadRequest)
+		s.logger.Printf("failed to apply %T: %v", cmd, err)
+		s.renderGameError(w, r, gr.Game, err)
 		return
 	}
-	gr.Notify()
 	s.renderGame(w, r, gr.Game)
-	//s.redirectToGame(w, r, gr.Game)
-	return
 }
 
-func (s *server) handlePostGameLeave(w http.ResponseWriter, r *http.Request) {
-	gr := getGameRoom(r)
-
-	gr.Mu.Lock()
-	defer gr.Mu.Unlock()
-	gr.Game.RemovePlayer(getPlayerId(r))
-	gr.Notify()
-
-	// TODO: is this necessary?
-	if gr.Game.IsEmpty() {
-		s.removeGame(gr.Game.Id)
-		http.Redirect(w, r, "/", http.StatusSeeOther)
-		return
-	}
-
-	s.renderGame(w, r, gr.Game)
+// reject renders the game for a player whose request could not be turned into
+// a command.
+func (s *server) reject(w http.ResponseWriter, r *http.Request, gr *room.Room, err error) {
+	gr.Mu.RLock()
+	defer gr.Mu.RUnlock()
+	s.renderGameError(w, r, gr.Game, err)
 }
 
-func (s *server) handlePostGameStart(w http.ResponseWriter, r *http.Request) {
+func (s *server) handlePostGamePlayers(w http.ResponseWriter, r *http.Request) {
 	gr := getGameRoom(r)
 
-	gr.Mu.Lock()
-	defer gr.Mu.Unlock()
-	p := gr.Game.GetPlayerById(getPlayerId(r))
-	if p == nil {
+	gr.Mu.RLock()
+	if gr.Game.GetPlayerById(getPlayerId(r)) != nil {
+		defer gr.Mu.RUnlock()
 		s.renderGame(w, r, gr.Game)
 		return
 	}
-	err := gr.Game.Start()
-	if err != nil {
-		s.logger.Printf("failed to start game: %v", err)
-	}
-	gr.Notify()
+	gr.Mu.RUnlock()
 
-	s.renderGame(w, r, gr.Game)
+	s.submit(w, r, gr, room.Join{Name: r.FormValue("name")})
 }
 
-func (s *server) handlePostGameDecide(w http.ResponseWriter, r *http.Request) {
-	gr := getGameRoom(r)
-	direction := r.PathValue("direction")
+func (s *server) handlePostGameBots(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, r, getGameRoom(r), room.AddBot{Strategy: r.FormValue("strategy")})
+}
 
-	gr.Mu.Lock()
-	defer gr.Mu.Unlock()
+func (s *server) handlePostGameBotsRemove(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, r, getGameRoom(r), room.RemoveBot{Name: r.FormValue("name")})
+}
 
-	err := gr.Game.DecideHandOrientation(getPlayerId(r), direction == "down")
+func (s *server) handlePostGameLeave(w http.ResponseWriter, r *http.Request) {
+	gr := getGameRoom(r)
+
+	_, err := gr.Submit(r.Context(), getPlayerId(r), room.Leave{})
 	if err != nil {
-		s.logger.Printf("failed to decide hand orientation: %v", err)
-		s.renderGame(w, r, gr.Game)
+		s.logger.Printf("failed to leave: %v", err)
+	}
+
+	gr.Mu.RLock()
+	abandoned := !gr.Game.HasHumans()
+	gr.Mu.RUnlock()
+	// Removing the room waits for its actor, which may be waiting for Mu
+	if abandoned {
+		s.removeGame(gr.Game.Id)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
-	gr.Notify()
 
+	gr.Mu.RLock()
+	defer gr.Mu.RUnlock()
 	s.renderGame(w, r, gr.Game)
 }
 
+func (s *server) handlePostGameTraining(w http.ResponseWriter, r *http.Request) {
+	setting := r.PathValue("setting")
+	s.submit(w, r, getGameRoom(r), room.SetTraining{Enabled: setting == "on"})
+}
+
+func (s *server) handlePostGameStart(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, r, getGameRoom(r), room.Start{})
+}
+
+func (s *server) handlePostGameDecide(w http.ResponseWriter, r *http.Request) {
+	direction := r.PathValue("direction")
+	s.submit(w, r, getGameRoom(r), room.Play{Action: game.OrientAction{Flip: direction == "down"}})
+}
+
 func (s *server) handlePostGamePresent(w http.ResponseWriter, r *http.Request) {
 	gr := getGameRoom(r)
+
 	presentationStr := r.PathValue("presentation")
 	presentationElements := strings.Split(presentationStr, "-")
 	if len(presentationElements) != 3 {
 		s.logger.Printf("invalid presentation: malformed argument: %s", presentationStr)
-		s.renderGame(w, r, gr.Game)
+		s.reject(w, r, gr, errMalformedRequest)
 		return
 	}
 	var presentationInts [3]int
@@ -240,31 +444,72 @@ func (s *server) handlePostGamePresent(w http.ResponseWriter, r *http.Request) {
 		presentationInts[i] = val
 		if err != nil {
 			s.logger.Printf("invalid presentation: %v", err)
-			s.renderGame(w, r, gr.Game)
+			s.reject(w, r, gr, errMalformedRequest)
 			return
 		}
 	}
 
-	gr.Mu.Lock()
-	defer gr.Mu.Unlock()
-
+	// The card is looked up in the hand the player saw, so their presentation
+	// is rejected if their hand has changed since.
+	version, ok, err := requestVersion(r)
+	if err != nil {
+		s.reject(w, r, gr, err)
+		return
+	}
+	gr.Mu.RLock()
+	stale := ok && gr.Game.Version() != version
+	start := -1
 	p := gr.Game.GetPlayerById(getPlayerId(r))
-	start := slices.Index(p.Hand, game.Card{presentationInts[0], presentationInts[1]})
+	if p != nil {
+		start = slices.Index(p.Hand, game.Card{presentationInts[0], presentationInts[1]})
+	}
+	gr.Mu.RUnlock()
+	if stale {
+		s.reject(w, r, gr, game.ErrStaleVersion)
+		return
+	}
+	if p == nil {
+		s.reject(w, r, gr, game.ErrPlayerNotFound)
+		return
+	}
 	if start == -1 {
 		s.logger.Print("invalid presentation: card not in hand")
-		s.renderGame(w, r, gr.Game)
+		s.reject(w, r, gr, game.ErrOutOfRange)
 		return
 	}
 
-	err := gr.Game.Present(getPlayerId(r), start, start+presentationInts[2])
+	s.submit(w, r, gr, room.Play{Action: game.PresentAction{Start: start, End: start + presentationInts[2]}})
+}
+
+func (s *server) handlePostGameProspect(w http.ResponseWriter, r *http.Request) {
+	gr := getGameRoom(r)
+
+	side, direction, ok := strings.Cut(r.FormValue("card"), "-")
+	if !ok || (side != "left" && side != "right") || (direction != "up" && direction != "down") {
+		s.logger.Printf("invalid prospect: malformed card: %s", r.FormValue("card"))
+		s.reject(w, r, gr, errMalformedRequest)
+		return
+	}
+	position, err := strconv.Atoi(r.FormValue("position"))
 	if err != nil {
-		s.logger.Printf("failed to present: %v", err)
-		s.renderGame(w, r, gr.Game)
+		s.logger.Printf("invalid prospect: malformed position: %s", r.FormValue("position"))
+		s.reject(w, r, gr, errMalformedRequest)
 		return
 	}
-	gr.Notify()
 
-	s.renderGame(w, r, gr.Game)
+	s.submit(w, r, gr, room.Play{Action: game.ProspectAction{
+		Left:     side == "left",
+		Flip:     direction == "down",
+		Position: position,
+	}})
+}
+
+func (s *server) handlePostGamePass(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, r, getGameRoom(r), room.Play{Action: game.PassAction{}})
+}
+
+func (s *server) handlePostGameReady(w http.ResponseWriter, r *http.Request) {
+	s.submit(w, r, getGameRoom(r), room.Play{Action: game.ReadyAction{}})
 }
 
 func (s *server) handleGetGame(w http.ResponseWriter, r *http.Request) {
@@ -290,6 +535,29 @@ func (s *server) handleGetGameSse(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Content-Type", "text/event-stream")
+
+	clients := s.sseClients.Add(1)
+	defer s.sseClients.Add(-1)
+	if clients > s.maxSseClients {
+		// Browsers give up on streams that fail, but reconnect to ones that
+		// end. Ending the stream with a long retry hint has clients back off.
+		err := writeSseEvent(w, sseEvent{
+			Retry: sseRetry(sseOverloadedRetry),
+			Event: sseEventToast,
+			Data:  "The server is busy. Live updates will resume shortly.",
+		})
+		if err != nil {
+			s.logger.Printf("failed to write to SSE output: %v", err)
+		}
+		flusher.Flush()
+		return
+	}
+
+	err := writeSseEvent(w, sseEvent{Retry: sseRetry(sseDefaultRetry)})
+	if err != nil {
+		s.logger.Printf("failed to write to SSE output: %v", err)
+		return
+	}
 	flusher.Flush()
 
 	keepAliveTicker := time.NewTicker(sseKeepAliveInterval)
@@ -297,26 +565,49 @@ func (s *server) handleGetGameSse(w http.ResponseWriter, r *http.Request) {
 		keepAliveTicker.Stop()
 	}()
 
-	notify := gr.Listen(r.Context())
+	// The room sends the current state straight away, and then whenever
+	// something this player can see changes.
+	updates := gr.Listen(r.Context(), getPlayerId(r), s.renderGameSse)
 
-	buf := &bytes.Buffer{}
+	// version is the Version of the game the client last saw. A reconnecting
+	// client reports it in the Last-Event-ID header. Otherwise it is unknown,
+	// since the page may have been loaded long ago.
+	version := lastEventVersion(r.Header.Get("Last-Event-ID"))
 
-	render := func() {
+	send := func(u room.Update) {
+		// Orientation advice changes what a player sees without changing
+		// the version, so only updates with nothing new are skipped.
+		if u.Version == version && u.State == nil {
+			return
+		}
+		events := []sseEvent{{Event: sseEventState, Data: string(u.State)}}
+		if u.State == nil {
+			events[0] = sseEvent{Event: sseEventVersion, Data: strconv.Itoa(u.Version)}
+		}
 		gr.Mu.RLock()
-		defer gr.Mu.RUnlock()
-		s.renderGameSse(buf, r, gr.Game)
-		_, err := fmt.Fprintf(w, "data: %s\n\n", strings.Replace(buf.String(), "\n", "", -1))
-		if err != nil {
-			s.logger.Printf("failed to write to SSE output: %v", err)
+		events = append(events, describeEvents(gr.Game, getPlayerId(r), version, u.Version)...)
+		gr.Mu.RUnlock()
+
+		events[len(events)-1].Id = strconv.Itoa(u.Version)
+		version = u.Version
+		for _, e := range events {
+			err := writeSseEvent(w, e)
+			if err != nil {
+				s.logger.Printf("failed to write to SSE output: %v", err)
+				return
+			}
 		}
-		buf.Reset()
+		flusher.Flush()
 	}
 
 	for {
 		select {
-		case <-notify:
-			render()
-			flusher.Flush()
+		case u, ok := <-updates:
+			if !ok {
+				// The room has been closed
+				return
+			}
+			send(u)
 			keepAliveTicker.Reset(sseKeepAliveInterval)
 		case <-keepAliveTicker.C:
 			// Send an empty SSE comment to keep connection alive
@@ -325,6 +616,19 @@ func (s *server) handleGetGameSse(w http.ResponseWriter, r *http.Request) {
 				s.logger.Printf("failed to write to SSE output: %v", err)
 			}
 			flusher.Flush()
+		case <-gr.ShuttingDown():
+			// Let the client know why the stream is ending. It will reconnect
+			// once the server is back.
+			err := writeSseEvent(w, sseEvent{
+				Retry: sseRetry(sseDefaultRetry),
+				Event: sseEventToast,
+				Data:  "The server is restarting. Reconnecting…",
+			})
+			if err != nil {
+				s.logger.Printf("failed to write to SSE output: %v", err)
+			}
+			flusher.Flush()
+			return
 		case <-r.Context().Done():
 			return
 		}